@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	sdl "github.com/rosshpayne/graph-sdl/ast"
-	pse "github.com/rosshpayne/graph-sdl/parser"
 )
 
 type UnresolvedMap sdl.UnresolvedMap //¬333map[Name_]*sdl.GQLtype
@@ -26,6 +25,20 @@ func (sc *Scalar_) Exists() bool {
 	return false
 }
 
+// Variable_ is a reference, $name, to an operation variable. It is the value of arguments parsed
+// by Parser.Parse, which leaves variables unsubstituted.
+type Variable_ string
+
+func (v Variable_) ValueNode() {}
+
+// IsType of a variable is not known until it is bound to a value.
+func (v Variable_) IsType() sdl.TypeFlag_ {
+	return sdl.ILLEGAL
+}
+func (v Variable_) String() string {
+	return "$" + string(v)
+}
+
 // ======== type system =========
 
 //type NamedGQLtype sdl.Name_
@@ -53,7 +66,6 @@ type Document struct {
 
 func (d Document) String() string {
 	var s strings.Builder
-	tc = 2
 
 	for _, stmt := range d.Statements {
 		s.WriteString(stmt.AST.String())
@@ -125,7 +137,12 @@ type FragmentDef interface {
 	// parse
 	AssignTypeCond(string, *sdl.Loc_, *[]error)
 	//	Executable
-	AssignTypeCondAST(*[]error, *pse.Cache_)
+	AssignTypeCondAST(*[]error, TypeFetcher)
+}
+
+// TypeFetcher sources the AST of a SDL type by name. It is satisfied by the parser's type cache bound to its logger.
+type TypeFetcher interface {
+	FetchAST(sdl.NameValue_) (sdl.GQLTypeProvider, error)
 }
 
 // // == ExecutableDefinition - end
@@ -192,7 +209,7 @@ func (o *OperationStmt) CheckInputValueType(err *[]error) {
 func (o *OperationStmt) CheckIsInputType(err *[]error) {
 	for _, p := range o.Variable {
 		if !sdl.IsInputType(p.Type) {
-			*err = append(*err, fmt.Errorf(`Argument "%s" type "%s", is not an input type %s`, p.Name_, p.Type.Name, p.Type.Name_.AtPosition()))
+			*err = append(*err, fmt.Errorf(`Variable "$%s" type "%s" is not an input type %s`, p.Name_, p.Type.Name, p.Type.Name_.AtPosition()))
 		}
 		//	_ := p.DefaultVal.isType() // e.g. scalar, int | List
 	}
@@ -231,15 +248,9 @@ func (o *OperationStmt) String() string { // Query will now satisfy Node interfa
 	}
 	s.WriteString("{ ")
 	for _, v := range o.SelectionSet {
-		s.WriteString(v.String())
-	}
-	for i := tc - 1; i > 0; i-- {
-		s.WriteString("\n")
-		for i := i; i > 0; i-- {
-			s.WriteString(fmt.Sprintf("\t"))
-		}
-		s.WriteString("}")
+		s.WriteString(selectionString(v, defaultIndent))
 	}
+	s.WriteString("\n\t}")
 	return s.String()
 }
 
@@ -324,21 +335,14 @@ func (f *FragmentStmt) AssignTypeCond(input string, loc *sdl.Loc_, err *[]error)
 
 func (f *FragmentStmt) String() string { // Query will now satisfy Node interface and complete GQLStmtProvider
 	var s strings.Builder
-	tc = 1
 	s.WriteString("\nfragment ")
 	s.WriteString(fmt.Sprintf("%s on %s ", f.Name, f.TypeCond))
 
 	s.WriteString("{ ")
 	for _, v := range f.SelectionSet {
-		s.WriteString(v.String())
-	}
-	for i := tc; i > 0; i-- {
-		s.WriteString("\n")
-		for i := i; i > 0; i-- {
-			s.WriteString(fmt.Sprintf("\t"))
-		}
-		s.WriteString("}")
+		s.WriteString(selectionString(v, 1))
 	}
+	s.WriteString("\n\t}")
 	return s.String()
 }
 
@@ -353,7 +357,7 @@ func (f *FragmentStmt) SolicitDependents(unresolved sdl.UnresolvedMap) {
 	}
 }
 
-func (f *FragmentStmt) AssignTypeCondAST(err *[]error, cache *pse.Cache_) {
+func (f *FragmentStmt) AssignTypeCondAST(err *[]error, cache TypeFetcher) {
 	// TODO - fix  dont use cache in type stmt methos
 	if f.TypeCondAST == nil {
 		x, err_ := cache.FetchAST(f.TypeCond.Name)
@@ -386,7 +390,23 @@ func (f *FragmentStmt) StmtName() StmtName_ {
 	return StmtName_(f.Name.String())
 }
 
-var tc = 2
+// defaultIndent is the tab depth of the selection set of an operation statement.
+// The String methods of the selection set types thread the current depth
+// rather than share a counter, so documents can be printed concurrently.
+const defaultIndent = 2
+
+// selectionString prints a selection set member at tab depth tc.
+func selectionString(v SelectionSetProvider, tc int) string {
+	switch x := v.(type) {
+	case *Field:
+		return x.string(tc)
+	case *InlineFragment:
+		return x.string(tc)
+	case *FragmentSpread:
+		return x.string(tc)
+	}
+	return v.String()
+}
 
 // =============== SelectionSet Types =====================
 
@@ -424,6 +444,10 @@ func (f *FragmentSpread) StmtType() string {
 }
 
 func (f *FragmentSpread) String() string {
+	return f.string(defaultIndent)
+}
+
+func (f *FragmentSpread) string(tc int) string {
 	var s strings.Builder
 	s.WriteString("\n")
 	for i := tc; i > 0; i-- {
@@ -483,7 +507,7 @@ func (f *InlineFragment) GetSelectionSet() []SelectionSetProvider {
 	return f.SelectionSet
 }
 
-func (f *InlineFragment) AssignTypeCondAST(err *[]error, cache *pse.Cache_) {
+func (f *InlineFragment) AssignTypeCondAST(err *[]error, cache TypeFetcher) {
 	//
 	// ...TypeCondition-opt Directives- opt SelectionSet
 	//
@@ -517,9 +541,12 @@ func (f *InlineFragment) AssignTypeCondAST(err *[]error, cache *pse.Cache_) {
 func (f *InlineFragment) CheckInputValueType(err *[]error) {}
 
 func (f *InlineFragment) String() string { // Query will now satisfy Node interface and complete GQLStmtProvider
+	return f.string(defaultIndent)
+}
+
+func (f *InlineFragment) string(tc int) string {
 	var s strings.Builder
 	s.WriteString("\n")
-	tabs := tc
 	for i := tc; i > 0; i-- {
 		s.WriteString(fmt.Sprintf("\t"))
 	}
@@ -545,21 +572,13 @@ func (f *InlineFragment) String() string { // Query will now satisfy Node interf
 		}
 		//s.WriteString("Len " + strconv.Itoa(len(f.SelectionSet)))
 		for _, v := range f.SelectionSet {
-			s.WriteString(v.String())
+			s.WriteString(selectionString(v, tc))
 		}
 		s.WriteString("\n")
 		for i := 0; i < tc; i++ {
 			s.WriteString("\t")
 		}
 		s.WriteString("}")
-		tc--
-	}
-	for i := tc; i > tabs; i-- {
-		s.WriteString("\n")
-		for i := i; i > tabs; i-- {
-			s.WriteString(fmt.Sprintf("\t"))
-		}
-		s.WriteString("}")
 	}
 	return s.String()
 }
@@ -635,7 +654,6 @@ func (f *Field) StmtType() string { // to support NameI - not relevant for Field
 // 	a.Arguments = append(a.Arguments, ss)
 // }
 func (f *Field) ExpandArguments(root *sdl.Field_, err *[]error) (failed bool) {
-	fmt.Println(" = = = = = = = = = = = = = = = = = = = = = = =  ExpandArguments = = = = = = = = = = = = = = = = ")
 	for _, rfa := range root.ArgumentDefs {
 		var found bool
 		for _, fa := range f.Arguments { // sdl.Arguments_
@@ -709,6 +727,10 @@ func (f *Field) AssignAlias(input string, loc *sdl.Loc_, err *[]error) {
 }
 
 func (f *Field) String() string {
+	return f.string(defaultIndent)
+}
+
+func (f *Field) string(tc int) string {
 	var s strings.Builder
 	s.WriteString("\n")
 	for i := 0; i < tc; i++ {
@@ -737,14 +759,13 @@ func (f *Field) String() string {
 		}
 		//	s.WriteString("Len " + strconv.Itoa(len(f.SelectionSet)))
 		for _, v := range f.SelectionSet {
-			s.WriteString(v.String())
+			s.WriteString(selectionString(v, tc))
 		}
 		s.WriteString("\n")
 		for i := 0; i < tc; i++ {
 			s.WriteString("\t")
 		}
 		s.WriteString("}")
-		tc--
 	}
 
 	return s.String()
@@ -0,0 +1,93 @@
+// Package auth enforces the access rules declared on SDL fields by the @auth directive, e.g.
+//
+//	posts: [Post] @auth(requires: ["reader"])
+//
+// against the principal carried in the context of a request. A principal may access a field
+// if it holds at least one of the roles the field requires. Fields without the directive are open to all.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+)
+
+// Directive is the definition of @auth, which must be included in a schema that uses it.
+const Directive = `directive @auth(requires: [String!]) on FIELD_DEFINITION`
+
+// Forbidden is the code of the error of a field denied to the principal.
+const Forbidden = "FORBIDDEN"
+
+// ErrForbidden is wrapped by the errors of Authorize.
+var ErrForbidden = errors.New(Forbidden)
+
+// Principal is the identity a request is made on behalf of.
+type Principal struct {
+	ID    string
+	Roles []string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal carried by ctx.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Requires returns the roles required by the @auth directive of field f. ok is false if the field has no directive.
+func Requires(f *sdl.Field_) (roles []string, ok bool) {
+	for _, d := range f.Directives {
+		if d.Name_.Name != "@auth" {
+			continue
+		}
+		ok = true
+		for _, arg := range d.Arguments {
+			if !arg.Name_.EqualString("requires") || arg.Value == nil {
+				continue
+			}
+			switch v := arg.Value.InputValueProvider.(type) {
+			case sdl.List_:
+				for _, iv := range v {
+					roles = append(roles, iv.InputValueProvider.String())
+				}
+			default:
+				roles = append(roles, v.String())
+			}
+		}
+	}
+	return roles, ok
+}
+
+// Authorize returns an error wrapping ErrForbidden if the principal carried by ctx may not access field f of type parentType.
+func Authorize(ctx context.Context, parentType string, f *sdl.Field_) error {
+	roles, ok := Requires(f)
+	if !ok {
+		return nil
+	}
+	if p, ok := PrincipalFrom(ctx); ok {
+		for _, r := range roles {
+			if p.HasRole(r) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf(`%w: field "%s" of type "%s" requires role %s`, ErrForbidden, f.Name_, parentType, strings.Join(roles, " or "))
+}
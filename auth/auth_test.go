@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	lex "github.com/rosshpayne/graph-sdl/lexer"
+	pse "github.com/rosshpayne/graph-sdl/parser"
+)
+
+func fields(t *testing.T, input string) []*sdl.Field_ {
+	p := pse.New(lex.New(input))
+	stmt := p.ParseStatement()
+	if errs := p.Getperror(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	return stmt.(*sdl.Object_).FieldSet
+}
+
+func TestAuthorize(t *testing.T) {
+
+	f := fields(t, `type Author {
+		name: String
+		books: Int @auth(requires: ["reader", "admin"])
+		royalties: Float @auth(requires: ["admin"])
+	}`)
+	if _, ok := Requires(f[0]); ok {
+		t.Errorf("Expected no @auth on name")
+	}
+	if roles, ok := Requires(f[1]); !ok || strings.Join(roles, ",") != "reader,admin" {
+		t.Errorf("Unexpected roles %v", roles)
+	}
+
+	reader := WithPrincipal(context.Background(), &Principal{ID: "u1", Roles: []string{"reader"}})
+	for i, v := range []struct {
+		ctx     context.Context
+		allowed []bool
+	}{
+		{context.Background(), []bool{true, false, false}},
+		{reader, []bool{true, true, false}},
+		{WithPrincipal(context.Background(), &Principal{Roles: []string{"admin"}}), []bool{true, true, true}},
+	} {
+		for j, fld := range f {
+			err := Authorize(v.ctx, "Author", fld)
+			if (err == nil) != v.allowed[j] {
+				t.Errorf("Case %d field %s: unexpected result %v", i, fld.Name_, err)
+			}
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				t.Errorf("Expected ErrForbidden, got %s", err)
+			}
+		}
+	}
+	if p, ok := PrincipalFrom(reader); !ok || p.ID != "u1" {
+		t.Errorf("Expected principal u1")
+	}
+}
@@ -0,0 +1,208 @@
+// Command gql runs a GraphQL query against a schema held in local SDL files, without a schema store.
+//
+// Usage:
+//
+//	gql -schema 'schema/*.graphql' [-query file] [-variables file] [-operation name] [-stubs file]
+//
+// The query is read from standard input when no query file is given. Resolvers are stubbed by a
+// JSON file mapping each resolver path to the response it returns, e.g.
+//
+//	{"Query/hero": {"Human": [{"id": "1", "name": "Luke"}]}}
+//
+// The response is written to standard output as JSON, with the data of the query, if it was executed,
+// and its errors. The exit status is 1 when there are errors.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/cmd/internal/quiet"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/parser"
+)
+
+var (
+	schemaFiles = flag.String("schema", "", "SDL files of the schema, a file name or glob pattern")
+	queryFile   = flag.String("query", "", "file of the query document, standard input when not given")
+	varsFile    = flag.String("variables", "", "JSON file of the variable values")
+	operation   = flag.String("operation", "", "name of the operation to execute, all operations when not given")
+	stubsFile   = flag.String("stubs", "", "JSON file of the response of each resolver by its path")
+	timeout     = flag.Duration("timeout", parser.ResolverTimeoutMS*time.Millisecond, "resolver timeout")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gql -schema files [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(*schemaFiles) == 0 || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	result, errs := run()
+	resp, err := parser.NewResponse(result, errs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
+
+func run() (result string, errs []error) {
+	var s *parser.Schema
+	quiet.Run(func() { s, errs = parser.LoadSchemaFiles(*schemaFiles) })
+	if len(errs) > 0 {
+		return "", errs
+	}
+	var (
+		query []byte
+		err   error
+	)
+	if len(*queryFile) > 0 {
+		query, err = ioutil.ReadFile(*queryFile)
+	} else {
+		query, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return "", []error{err}
+	}
+	p := parser.NewWithSchema(lexer.New(string(query)), s)
+	p.SetResolverTimeout(*timeout)
+	if len(*operation) > 0 {
+		p.SetExecStmt(*operation)
+	}
+	if len(*varsFile) > 0 {
+		var vars map[string]interface{}
+		if err := decodeFile(*varsFile, &vars); err != nil {
+			return "", []error{err}
+		}
+		p.SetVariables(vars)
+	}
+	if len(*stubsFile) > 0 {
+		var stubs map[string]interface{}
+		if err := decodeFile(*stubsFile, &stubs); err != nil {
+			return "", []error{err}
+		}
+		for path, resp := range stubs {
+			lit, err := literal(resp)
+			if err != nil {
+				return "", []error{fmt.Errorf(`Stub for "%s": %w`, path, err)}
+			}
+			p.Resolver.Register(path, stub(lit))
+		}
+	}
+	quiet.Run(func() {
+		if _, errs = p.ParseDocument(); len(errs) == 0 {
+			result, errs = p.ExecuteDocument()
+		}
+	})
+	return result, errs
+}
+
+// decodeFile decodes the JSON in file f into v, keeping numbers as written.
+func decodeFile(f string, v interface{}) error {
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", f, err)
+	}
+	return nil
+}
+
+// stub returns a resolver that responds with resp.
+func stub(resp string) func(context.Context, sdl.InputValueProvider, sdl.ObjectVals) <-chan string {
+	return func(context.Context, sdl.InputValueProvider, sdl.ObjectVals) <-chan string {
+		gql := make(chan string, 1)
+		gql <- resp
+		return gql
+	}
+}
+
+// literal returns JSON value v in the syntax of a resolver response, {name: value ...}.
+func literal(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return quote(x), nil
+	case []interface{}:
+		items := make([]string, len(x))
+		for i, e := range x {
+			lit, err := literal(e)
+			if err != nil {
+				return "", err
+			}
+			items[i] = lit
+		}
+		return "[" + strings.Join(items, " ") + "]", nil
+	case map[string]interface{}:
+		names := make([]string, 0, len(x))
+		for k := range x {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for i, k := range names {
+			lit, err := literal(x[k])
+			if err != nil {
+				return "", err
+			}
+			names[i] = k + ": " + lit
+		}
+		return "{" + strings.Join(names, " ") + "}", nil
+	default:
+		// json.Number and bool
+		return fmt.Sprint(x), nil
+	}
+}
+
+// quote returns s as a string of a resolver response. Escape sequences are copied as written from the response
+// to the result, but the response lexer ends a string at any ", even one escaped as \", so quotes are written as \u0022.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\u0022`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				break
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,114 @@
+// Command gqlfmt formats GraphQL documents in the canonical layout of package printer.
+//
+// Usage:
+//
+//	gqlfmt [flags] [path ...]
+//
+// Each file named, and each .graphql or .gql file in the directories named, is reformatted in
+// place. With no path, the document read from standard input is written to standard output.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rosshpayne/graphql/printer"
+)
+
+var (
+	list     = flag.Bool("l", false, "list files whose formatting differs, without reformatting them")
+	indent   = flag.Int("indent", 2, "spaces of indentation for each level of selection set")
+	tabs     = flag.Bool("tabs", false, "indent with tabs")
+	comments = flag.Bool("comments", true, "keep comments")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gqlfmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := printer.Config{Indent: strings.Repeat(" ", *indent), Comments: *comments}
+	if *tabs {
+		cfg.Indent = "\t"
+	}
+	if flag.NArg() == 0 {
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		out, errs := cfg.Source(src)
+		if report("<standard input>", errs) {
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+	failed := false
+	for _, path := range flag.Args() {
+		err := filepath.Walk(path, func(f string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || f != path && !isGraphQL(f) {
+				return nil
+			}
+			if !format(cfg, f, info.Mode()) {
+				failed = true
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func isGraphQL(f string) bool {
+	ext := filepath.Ext(f)
+	return ext == ".graphql" || ext == ".gql"
+}
+
+// format reformats file f in place, or lists it when -l is given. It reports whether f was formatted.
+func format(cfg printer.Config, f string, mode os.FileMode) bool {
+	src, err := ioutil.ReadFile(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	out, errs := cfg.Source(src)
+	if report(f, errs) {
+		return false
+	}
+	if bytes.Equal(src, out) {
+		return true
+	}
+	if *list {
+		fmt.Println(f)
+		return true
+	}
+	if err := ioutil.WriteFile(f, out, mode.Perm()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	return true
+}
+
+// report prints the errors of document f and reports whether there are any.
+func report(f string, errs []error) bool {
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", f, e)
+	}
+	return len(errs) > 0
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/cmd/internal/quiet"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/parser"
+	"github.com/rosshpayne/graphql/token"
+)
+
+// diagnostic is an error or warning at a position of a file. Line is zero when the position is not known.
+type diagnostic struct {
+	file      string
+	line, col int
+	msg       string
+	warning   bool
+}
+
+func (d diagnostic) String() string {
+	var s strings.Builder
+	s.WriteString(d.file)
+	if d.line > 0 {
+		fmt.Fprintf(&s, ":%d:%d", d.line, d.col)
+	}
+	s.WriteString(": ")
+	if d.warning {
+		s.WriteString("warning: ")
+	}
+	s.WriteString(d.msg)
+	return s.String()
+}
+
+// file is a GraphQL document of the project being linted.
+type file struct {
+	name    string
+	src     string
+	frags   []*fragment
+	ops     []*operation
+	spreads []string // fragments spread by the file's operations
+}
+
+// operation is an operation statement of a file.
+type operation struct {
+	text    string
+	loc     token.Pos // of the first token
+	spreads []string
+}
+
+// fragment is a fragment statement, which operations of any file may spread.
+type fragment struct {
+	name    string
+	file    *file
+	text    string
+	loc     token.Pos // of the fragment keyword
+	nameLoc token.Pos
+	spreads []string
+}
+
+// linter validates the files of a project against a schema. Fragments are shared by all files.
+type linter struct {
+	schema     *parser.Schema
+	deprecated bool // warn of the use of deprecated fields
+	files      []*file
+	frags      map[string]*fragment // first definition of each fragment
+	diags      []diagnostic
+	seen       map[string]bool
+}
+
+func newLinter(s *parser.Schema, deprecated bool) *linter {
+	return &linter{schema: s, deprecated: deprecated, frags: make(map[string]*fragment), seen: make(map[string]bool)}
+}
+
+func (lt *linter) report(d diagnostic) {
+	if k := d.String(); !lt.seen[k] {
+		lt.seen[k] = true
+		lt.diags = append(lt.diags, d)
+	}
+}
+
+// add scans document src of file name for its fragments and the fragments each statement spreads.
+func (lt *linter) add(name, src string) {
+	f := &file{name: name, src: src}
+	lt.files = append(lt.files, f)
+	var (
+		frag   *fragment  // fragment statement being scanned, nil in an operation
+		op     *operation // operation statement being scanned, nil in a fragment
+		stmt   bool       // scanning a statement
+		start  int        // offset of the statement
+		depth  int        // open selection sets
+		values int        // open ( and [, whose braces enclose input object values
+		prev   token.TokenType
+	)
+	l := lexer.New(src)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if !stmt {
+			stmt, frag, op, start = true, nil, nil, tok.Offset
+			if tok.Type == token.FRAGMENT {
+				frag = &fragment{file: f, loc: tok.Loc}
+				f.frags = append(f.frags, frag)
+			} else {
+				op = &operation{loc: tok.Loc}
+				f.ops = append(f.ops, op)
+			}
+		}
+		switch tok.Type {
+		case token.IDENT:
+			switch {
+			case prev == token.EXPAND && frag != nil:
+				frag.spreads = append(frag.spreads, tok.Literal)
+			case prev == token.EXPAND:
+				f.spreads = append(f.spreads, tok.Literal)
+				op.spreads = append(op.spreads, tok.Literal)
+			case prev == token.FRAGMENT && frag != nil:
+				frag.name, frag.nameLoc = tok.Literal, tok.Loc
+			}
+		case token.LPAREN, token.LBRACKET:
+			values++
+		case token.RPAREN, token.RBRACKET:
+			values--
+		case token.LBRACE:
+			if values == 0 {
+				depth++
+			}
+		case token.RBRACE:
+			if values == 0 {
+				if depth--; depth == 0 {
+					stmt = false
+					if frag != nil {
+						frag.text = src[start:tok.End]
+					} else {
+						op.text = src[start:tok.End]
+					}
+				}
+			}
+		}
+		prev = tok.Type
+	}
+	for _, frag := range f.frags {
+		if len(frag.name) == 0 {
+			continue
+		}
+		if first, ok := lt.frags[frag.name]; ok {
+			if first.file != f {
+				lt.report(diagnostic{file: f.name, line: frag.nameLoc.Line, col: frag.nameLoc.Col,
+					msg: fmt.Sprintf(`Fragment "%s" is also defined at %s:%d:%d`, frag.name, first.file.name, first.nameLoc.Line, first.nameLoc.Col)})
+			}
+			continue
+		}
+		lt.frags[frag.name] = frag
+	}
+}
+
+// lint validates every file, then reports fragments no operation spreads. It returns the diagnostics
+// in file and position order.
+func (lt *linter) lint() []diagnostic {
+	used := make(map[string]bool)
+	var use func(names []string)
+	use = func(names []string) {
+		for _, nm := range names {
+			if frag, ok := lt.frags[nm]; ok && !used[nm] {
+				used[nm] = true
+				use(frag.spreads)
+			}
+		}
+	}
+	for _, f := range lt.files {
+		lt.lintFile(f)
+		use(f.spreads)
+	}
+	for _, f := range lt.files {
+		for _, frag := range f.frags {
+			if len(frag.name) > 0 && lt.frags[frag.name] == frag && !used[frag.name] {
+				lt.report(diagnostic{file: f.name, line: frag.nameLoc.Line, col: frag.nameLoc.Col, msg: fmt.Sprintf(`Fragment "%s" is not used`, frag.name)})
+			}
+		}
+	}
+	sort.SliceStable(lt.diags, func(i, j int) bool {
+		a, b := lt.diags[i], lt.diags[j]
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.col < b.col
+	})
+	return lt.diags
+}
+
+// segment is a part of the document validated for a file: the file itself, or a fragment of another file.
+type segment struct {
+	line   int // line of the document the segment starts
+	file   *file
+	origin int // line of the file the segment starts
+}
+
+// lintFile validates f with the fragments of other files it spreads appended. The use of deprecated fields
+// is reported from the document of f when it has no errors, otherwise from a document of each operation of f,
+// so an error does not hide the warnings of the operations that parsed.
+func (lt *linter) lintFile(f *file) {
+	own := make(map[string]bool)
+	need := f.spreads
+	for _, frag := range f.frags {
+		own[frag.name] = true
+		need = append(need, frag.spreads...)
+	}
+	doc, segs := lt.document(f, f.src, 1, own, need)
+	d, errs := lt.parse(doc)
+	for _, err := range errs {
+		lt.report(position(segs, err.Error()))
+	}
+	if !lt.deprecated {
+		return
+	}
+	if len(errs) == 0 {
+		lt.deprecations(segs, d)
+		return
+	}
+	for _, op := range f.ops {
+		if len(op.text) == 0 {
+			continue
+		}
+		// the operation is indented to its column, so its positions map back to the file
+		doc, segs := lt.document(f, strings.Repeat(" ", op.loc.Col-1)+op.text, op.loc.Line, nil, op.spreads)
+		d, _ := lt.parse(doc)
+		lt.deprecations(segs, d)
+	}
+}
+
+// document returns src, which starts at line origin of file f, with the fragments it spreads appended
+// unless they are own fragments of src. Each fragment is appended on lines of its own, indented to its
+// column in its file, so the positions of the document map back to the files of its segments.
+func (lt *linter) document(f *file, src string, origin int, own map[string]bool, need []string) (string, []segment) {
+	var (
+		doc  strings.Builder
+		segs = []segment{{line: 1, file: f, origin: origin}}
+		line = 1 + newlines(src)
+		seen = make(map[string]bool)
+	)
+	doc.WriteString(src)
+	for len(need) > 0 {
+		nm := need[0]
+		need = need[1:]
+		frag, ok := lt.frags[nm]
+		if own[nm] || seen[nm] || !ok {
+			continue
+		}
+		seen[nm] = true
+		doc.WriteString("\n" + strings.Repeat(" ", frag.loc.Col-1) + frag.text)
+		line++
+		segs = append(segs, segment{line: line, file: frag.file, origin: frag.loc.Line})
+		line += newlines(frag.text)
+		need = append(need, frag.spreads...)
+	}
+	return doc.String(), segs
+}
+
+// parse validates document doc. The document is returned with any errors, holding the statements that parsed.
+func (lt *linter) parse(doc string) (d *ast.Document, errs []error) {
+	p := parser.NewWithSchema(lexer.New(doc), lt.schema)
+	p.SetSharedFragments(true)
+	quiet.Run(func() { _, errs = p.ParseDocument() })
+	return p.Document(), errs
+}
+
+// deprecations warns of each field of the statements of d whose definition has the @deprecated directive.
+func (lt *linter) deprecations(segs []segment, d *ast.Document) {
+	if d == nil {
+		return
+	}
+	for _, stmt := range d.Statements {
+		switch s := stmt.AST.(type) {
+		case *ast.OperationStmt:
+			lt.deprecatedFields(segs, s.SelectionSet)
+		case *ast.FragmentStmt:
+			lt.deprecatedFields(segs, s.SelectionSet)
+		}
+	}
+}
+
+// deprecatedFields warns of each field of set whose definition has the @deprecated directive.
+func (lt *linter) deprecatedFields(segs []segment, set []ast.SelectionSetProvider) {
+	for _, s := range set {
+		switch x := s.(type) {
+		case *ast.Field:
+			if x.SDLfld != nil {
+				for _, d := range x.SDLfld.Directives {
+					if strings.TrimPrefix(d.Name.String(), "@") != "deprecated" {
+						continue
+					}
+					reason := "No longer supported"
+					for _, a := range d.Arguments {
+						if a.Name.String() == "reason" && a.Value != nil && a.Value.InputValueProvider != nil {
+							reason = a.Value.InputValueProvider.String()
+						}
+					}
+					name := x.Name.String()
+					if x.SDLRootAST != nil {
+						name = x.SDLRootAST.TypeName().String() + "." + name
+					}
+					diag := diagnostic{file: segs[0].file.name, msg: fmt.Sprintf(`Field "%s" is deprecated: %s`, name, reason), warning: true}
+					if x.Name.Loc != nil {
+						diag.locate(segs, x.Name.Loc.Line, x.Name.Loc.Column)
+					}
+					lt.report(diag)
+				}
+			}
+			lt.deprecatedFields(segs, x.SelectionSet)
+		case *ast.InlineFragment:
+			lt.deprecatedFields(segs, x.SelectionSet)
+		}
+	}
+}
+
+// position returns the diagnostic of error message msg. The parser gives the position of an error only in
+// its message, e.g. "at line: 3 column: 7" or "at line: 3, column: 7", which is removed from the message
+// and located in the file of the segment of the document it refers to.
+func position(segs []segment, msg string) diagnostic {
+	d := diagnostic{file: segs[0].file.name, msg: strings.TrimSpace(msg)}
+	i := strings.LastIndex(msg, " at line: ")
+	if i < 0 {
+		return d
+	}
+	for _, format := range []string{" at line: %d, column: %d", " at line: %d column: %d"} {
+		var line, col int
+		if n, _ := fmt.Sscanf(msg[i:], format, &line, &col); n < 2 {
+			continue
+		}
+		at := fmt.Sprintf(format, line, col)
+		d.msg = strings.TrimSpace(msg[:i] + strings.TrimPrefix(msg[i+len(at):], "."))
+		d.locate(segs, line, col)
+		break
+	}
+	return d
+}
+
+// locate positions d at line and column col of the document of segments segs, in the file of its segment.
+func (d *diagnostic) locate(segs []segment, line, col int) {
+	for i := len(segs) - 1; i >= 0; i-- {
+		if line >= segs[i].line {
+			d.file, d.line, d.col = segs[i].file.name, segs[i].origin+line-segs[i].line, col
+			return
+		}
+	}
+}
+
+// newlines returns the number of line terminators of s, each a line feed, carriage return or both.
+func newlines(s string) int {
+	return strings.Count(s, "\n") + strings.Count(s, "\r") - strings.Count(s, "\r\n")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rosshpayne/graphql/parser"
+)
+
+const lintSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		hero(episode: Episode = JEDI): Character
+		villain: Character @deprecated(reason: "Use hero")
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	type Character {
+		id: String!
+		name: String!
+		nick: String @deprecated
+		friends: [Character]
+	}`
+
+func TestLint(t *testing.T) {
+
+	s, errs := parser.NewSchema(lintSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		files [][2]string // name, source
+		diags []string
+	}{
+		{ // fragments are shared across files
+			files: [][2]string{
+				{"hero.graphql", `query Hero { hero(episode: JEDI) { ...heroFields } }`},
+				{"fragments.graphql", "# shared fragments\nfragment heroFields on Character {\n\tid\n\tfriends { ...names }\n}\nfragment names on Character { name }"},
+			},
+		},
+		{ // errors in a shared fragment are reported once, in its file
+			files: [][2]string{
+				{"a.graphql", `query A { hero { ...heroFields } }`},
+				{"b.graphql", `query B { hero(episode: EMPIRE) { ...heroFields id } }`},
+				{"fragments.graphql", "fragment heroFields on Character {\n\tid\n\tage\n}"},
+			},
+			diags: []string{
+				`fragments.graphql:3:2: Field "age" is not a member of "Character"`,
+			},
+		},
+		{
+			files: [][2]string{
+				{"hero.graphql", "query Hero {\n  hero(episode: SITH) { id }\n}"},
+				{"unused.graphql", "fragment unused on Character { id }\nquery Other { hero { ...missing } }"},
+			},
+			diags: []string{
+				`hero.graphql:2:17: "SITH" is not a member of Enum type Episode`,
+				`unused.graphql:1:10: Fragment "unused" is not used`,
+				`unused.graphql:2:25: Fragment definition "missing" not found`,
+			},
+		},
+		{ // warnings are given for documents without errors
+			files: [][2]string{
+				{"villain.graphql", "query Villain {\n  villain { ...nick }\n}"},
+				{"nick.graphql", "\tfragment nick on Character { nick }"},
+			},
+			diags: []string{
+				`nick.graphql:1:31: warning: Field "Character.nick" is deprecated: No longer supported`,
+				`villain.graphql:2:3: warning: Field "Query.villain" is deprecated: Use hero`,
+			},
+		},
+		{ // warnings are given for the operations of documents with errors
+			files: [][2]string{
+				{"errors.graphql", "query A { villain { id } }\nquery B { hero { age } }\nquery C { villain { nick age } }"},
+				{"syntax.graphql", "query A { villain { id } }\nquery B { hero { i*d } }"},
+			},
+			diags: []string{
+				`errors.graphql:1:11: warning: Field "Query.villain" is deprecated: Use hero`,
+				`errors.graphql:2:18: Field "age" is not a member of "hero" (SDL Object "Character")`,
+				`errors.graphql:3:11: warning: Field "Query.villain" is deprecated: Use hero`,
+				`errors.graphql:3:21: warning: Field "Character.nick" is deprecated: No longer supported`,
+				`errors.graphql:3:26: Field "age" is not a member of "villain" (SDL Object "Character")`,
+				`syntax.graphql:1:11: warning: Field "Query.villain" is deprecated: Use hero`,
+				`syntax.graphql:2:19: Illegal character "*"`,
+				`syntax.graphql:2:19: Expected an identifier for a fragment or inlinefragment got ILLEGAL.`,
+			},
+		},
+		{
+			files: [][2]string{
+				{"a.graphql", `query A { hero { ...names } } fragment names on Character { name }`},
+				{"b.graphql", `query B { hero { ...names } } fragment names on Character { id }`},
+			},
+			diags: []string{
+				`b.graphql:1:40: Fragment "names" is also defined at a.graphql:1:40`,
+			},
+		},
+	} {
+		lt := newLinter(s, true)
+		for _, f := range v.files {
+			lt.add(f[0], f[1])
+		}
+		diags := lt.lint()
+		var got []string
+		for _, d := range diags {
+			got = append(got, d.String())
+		}
+		if len(got) != len(v.diags) {
+			t.Errorf("Expected %d diagnostics, got %d: %q", len(v.diags), len(got), got)
+			continue
+		}
+		for i := range got {
+			if got[i] != v.diags[i] {
+				t.Errorf("Got:      %s\nExpected: %s", got[i], v.diags[i])
+			}
+		}
+	}
+}
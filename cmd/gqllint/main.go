@@ -0,0 +1,84 @@
+// Command gqllint validates the GraphQL operations of a project against a schema, without executing them.
+//
+// Usage:
+//
+//	gqllint -schema 'schema/*.graphql' [-deprecated=false] path ...
+//
+// Each file named, and each .graphql or .gql file in the directories named, is validated. Fragments
+// may be spread by operations of any file. Diagnostics are printed as file:line:col: message, and
+// uses of fields with the @deprecated directive are reported as warnings. The exit status is 1 when
+// there are errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/rosshpayne/graphql/cmd/internal/quiet"
+	"github.com/rosshpayne/graphql/parser"
+)
+
+var (
+	schemaFiles = flag.String("schema", "", "SDL files of the schema, a file name or glob pattern")
+	deprecated  = flag.Bool("deprecated", true, "warn of the use of deprecated fields")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gqllint -schema files [flags] path ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(*schemaFiles) == 0 || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var (
+		s    *parser.Schema
+		errs []error
+	)
+	quiet.Run(func() { s, errs = parser.LoadSchemaFiles(*schemaFiles) })
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Fprintln(os.Stderr, e)
+		}
+		os.Exit(2)
+	}
+	lt := newLinter(s, *deprecated)
+	failed := false
+	for _, path := range flag.Args() {
+		err := filepath.Walk(path, func(f string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || f != path && !isGraphQL(f) {
+				return nil
+			}
+			src, err := ioutil.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			lt.add(f, string(src))
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	for _, d := range lt.lint() {
+		fmt.Fprintln(os.Stdout, d)
+		failed = failed || !d.warning
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func isGraphQL(f string) bool {
+	ext := filepath.Ext(f)
+	return ext == ".graphql" || ext == ".gql"
+}
@@ -0,0 +1,22 @@
+// Package quiet discards the tracing graph-sdl writes to standard output, which would otherwise be
+// interleaved with the output of a command.
+package quiet
+
+import "os"
+
+// Run calls f with standard output discarded, restoring it when f returns. Output written by other
+// goroutines while f runs is discarded too, so commands call it only around the use of graph-sdl.
+func Run(f func()) {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		f()
+		return
+	}
+	stdout := os.Stdout
+	os.Stdout = null
+	defer func() {
+		os.Stdout = stdout
+		null.Close()
+	}()
+	f()
+}
@@ -0,0 +1,69 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rosshpayne/graphql/token"
+)
+
+// conformance is a corpus of source text and the tokens, "type literal line:col", the October 2021 grammar gives it.
+var conformance = []struct {
+	input  string
+	tokens string
+}{
+	// punctuators
+	{`! $ & ( ) ... : = @ [ ] { | }`, `! ! 1:1, $ $ 1:3, & & 1:5, ( ( 1:7, ) ) 1:9, ... ... 1:11, : : 1:15, = = 1:17, @ @ 1:19, [ [ 1:21, ] ] 1:23, { { 1:25, | | 1:27, } } 1:29`},
+	{`union U = A | B`, `IDENT union 1:1, IDENT U 1:7, = = 1:9, IDENT A 1:11, | | 1:13, IDENT B 1:15`},
+	{`type T implements A & B`, `TYPE type 1:1, IDENT T 1:6, IDENT implements 1:8, IDENT A 1:19, & & 1:21, IDENT B 1:23`},
+	{`.. x`, `ILLEGAL .. 1:1, IDENT x 1:4`},
+	{`.5`, `ILLEGAL . 1:1, Int 5 1:2`},
+	// names
+	{`_a1 A_b __typename`, `IDENT _a1 1:1, IDENT A_b 1:5, IDENT __typename 1:9`},
+	{`café`, `IDENT caf 1:1, ILLEGAL é 1:4`},
+	{`ñame`, `ILLEGAL ñ 1:1, IDENT ame 1:2`},
+	// int values
+	{`0 -0 123 -45`, `Int 0 1:1, Int -0 1:3, Int 123 1:6, Int -45 1:10`},
+	{`007`, `ILLEGAL 007 1:2`},
+	{`-01`, `ILLEGAL -01 1:3`},
+	{`- 1`, `ILLEGAL - 1:2, Int 1 1:3`},
+	{`-a`, `ILLEGAL -a 1:2`},
+	{`123abc x`, `ILLEGAL 123abc 1:4, IDENT x 1:8`},
+	{`0x1F`, `ILLEGAL 0x1F 1:2`},
+	{`[1,2,-13]`, `[ [ 1:1, Int 1 1:2, Int 2 1:4, Int -13 1:6, ] ] 1:9`},
+	// float values
+	{`1.5 -0.0 1e10 1E-3 2.5e+7`, `Float 1.5 1:1, Float -0.0 1:5, Float 1e10 1:10, Float 1E-3 1:15, Float 2.5e+7 1:20`},
+	{`1. x`, `ILLEGAL 1. 1:3, IDENT x 1:4`},
+	{`1.e5`, `ILLEGAL 1.e5 1:3`},
+	{`1e`, `ILLEGAL 1e 1:2`},
+	{`1e+ 2`, `ILLEGAL 1e+ 1:4, Int 2 1:5`},
+	{`1.5.`, `ILLEGAL 1.5. 1:4`},
+	{`1.2.3`, `ILLEGAL 1.2.3 1:4`},
+	{`00.5`, `ILLEGAL 00.5 1:2`},
+	{`1.5e3x`, `ILLEGAL 1.5e3x 1:6`},
+	// ignored tokens and line terminators
+	{"a, b,,c", `IDENT a 1:1, IDENT b 1:4, IDENT c 1:7`},
+	{"a # comment\nb # comment at the end", `IDENT a 1:1, IDENT b 2:1`},
+	{"a\rb\r\nc\nd", `IDENT a 1:1, IDENT b 2:1, IDENT c 3:1, IDENT d 4:1`},
+	{"\ufeffa", "BOM \ufeff 1:1, IDENT a 1:2"},
+	// values
+	{`"str" """block""" true false null`, `String str 1:1, RAWSTRING block 1:7, true true 1:19, false false 1:24, NULL null 1:30`},
+}
+
+func TestConformance(t *testing.T) {
+
+	for i, tt := range conformance {
+		l := New(tt.input)
+		var got []string
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			got = append(got, fmt.Sprintf("%s %s %d:%d", tok.Type, tok.Literal, tok.Loc.Line, tok.Loc.Col))
+			if len(got) > 50 {
+				t.Fatalf("conformance[%d] - lexer makes no progress: %s", i, got[len(got)-1])
+			}
+		}
+		if s := strings.Join(got, ", "); s != tt.tokens {
+			t.Errorf("conformance[%d] %q\n got:      %s\n expected: %s", i, tt.input, s, tt.tokens)
+		}
+	}
+}
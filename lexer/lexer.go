@@ -1,17 +1,22 @@
 package lexer
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 
+	"github.com/rosshpayne/graphql/logger"
 	"github.com/rosshpayne/graphql/token"
 )
 
 // Lexer parses an Input string (embedded in token pkg) and returns it as tokens - defined in token package.
 type Lexer struct {
 	//	Eloc  token.Pos // Loc of illegal char
-	input string
+	input []byte
 	cLoc  int    // Current rune Loc in input
 	rLoc  int    // Read rune Loc in input
 	ch    rune   // current rune under examination, added to token during lex processings
@@ -22,50 +27,139 @@ type Lexer struct {
 	//
 	buffer [2]token.Token // dual buffer to hold current and peek token
 	bi     int            // buffer index
+	log    logger.Logger
+	//
+	r    io.Reader // source of the input still to be read, nil once exhausted or when lexing a string
+	buf  []byte    // buffer of a streaming lexer, which input is a window of
+	base int       // offset of input in the whole input, more than zero once a streaming lexer drops input
+	//
+	trivia bool // keep the trivia of each token
 }
 
+// readSize is the least free space of the buffer of a streaming lexer for a read from its reader.
+const readSize = 4096
+
 func (l *Lexer) CLoc() int {
 	return l.cLoc
 }
 
 func (l *Lexer) Input() string {
-	return l.input
+	return string(l.input)
 }
 
 func (l *Lexer) Loc() (int, int) {
 	return l.Line, l.Col
 }
 func New(input string) *Lexer {
-	l := &Lexer{input: input, Line: 1}
+	l := &Lexer{input: []byte(input), Line: 1, log: logger.Nop()}
+	l.readRune() // prime lexer struct
+	return l
+}
+
+// NewReader returns a lexer that reads its input from r as it lexes. Only the input from the start of
+// the current token is held in memory, so Input and CLoc refer to that part of the input. Token positions
+// are the same as those of New over the same input. A read error ends the input and is reported by Err.
+func NewReader(r io.Reader) *Lexer {
+	l := &Lexer{r: r, buf: make([]byte, 2*readSize), Line: 1, log: logger.Nop()}
+	l.input = l.buf[:0]
 	l.readRune() // prime lexer struct
 	return l
 }
 
+// SetTrivia sets whether the lexer keeps the trivia of each token, as tooling such as a formatter needs: the
+// white space, commas and comments before the token in Leading and those after it on the same line in Trailing.
+// Trivia are not kept by default.
+func (l *Lexer) SetTrivia(keep bool) {
+	l.trivia = keep
+}
+
+// Err returns the error, other than io.EOF, that ended the input of a lexer created by NewReader.
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// fill reads from the lexer's reader until at least n bytes of input follow the rune under examination,
+// or the reader is exhausted. Reads append to the input in the free space of the buffer. When too little
+// is free the input is moved to the front of the buffer, which is first doubled if the input fills half of it,
+// so each byte is copied a constant number of times however long a token is.
+func (l *Lexer) fill(n int) {
+	for l.r != nil && len(l.input)-l.rLoc < n {
+		if cap(l.input)-len(l.input) < readSize {
+			if size := 2 * (len(l.input) + readSize); size > len(l.buf) {
+				l.buf = make([]byte, size)
+			}
+			l.input = l.buf[:copy(l.buf, l.input)]
+		}
+		m, err := l.r.Read(l.input[len(l.input):cap(l.input)])
+		l.input = l.input[:len(l.input)+m]
+		if err != nil {
+			if err != io.EOF {
+				l.err = err
+			}
+			l.r = nil
+		}
+	}
+}
+
+// lookingAt reports whether the input from offset i starts with s.
+func (l *Lexer) lookingAt(i int, s string) bool {
+	l.fill(i - l.rLoc + len(s))
+	return bytes.HasPrefix(l.input[i:], []byte(s))
+}
+
 func (l *Lexer) AtPosition() string {
 	return fmt.Sprintf("at line: %d, column: %d", l.Line, l.Col)
 }
 
+// SetLogger assigns the logger of the lexer. Nothing is logged by default.
+func (l *Lexer) SetLogger(log logger.Logger) {
+	l.log = log
+}
+
 func (l *Lexer) NextToken() *token.Token {
+	tok := l.nextToken()
+	if tok.Illegal || tok.Type == token.ILLEGAL {
+		l.log.Log(logger.Debug, "illegal token", "type", tok.Type, "literal", tok.Literal, "line", tok.Loc.Line, "column", tok.Loc.Col)
+	}
+	return tok
+}
+
+func (l *Lexer) nextToken() *token.Token {
+	if l.r != nil {
+		// drop the input already lexed
+		l.base += l.cLoc
+		l.input, l.rLoc, l.cLoc = l.input[l.cLoc:], l.rLoc-l.cLoc, 0
+	}
+	leading := l.readTrivia(false) // scan to next non-whitespace and return its value as a token
+	offset := l.base + l.end()
+	tok := l.readToken()
+	tok.Offset, tok.End = offset, l.base+l.end()
+	if l.trivia {
+		tok.Leading, tok.Trailing = leading, l.readTrivia(true)
+	}
+	return tok
+}
+
+// readToken reads the token starting at the rune under examination.
+func (l *Lexer) readToken() *token.Token {
 	var tok *token.Token
 	//	fmt.Printf("NextToken: %c\n", l.ch)
-	l.skipWhitespace() // scan to next non-whitespace and return its value as a token
-	//fmt.Printf("[%c]\n", l.ch)
 	switch l.ch {
 	case '\ufeff':
 		tok = l.newToken(token.BOM, l.ch)
-	case '#':
-		l.readToEol()
-		return l.NextToken()
 	case '.': // ... expand sequence
+		start := token.Pos{Line: l.Line, Col: l.Col}
 		if l.peekRune() == '.' {
 			//ch := l.ch
 			l.readRune()
 			if l.peekRune() == '.' {
 				//ch := l.ch
 				l.readRune()
-				tok = l.newToken(token.EXPAND, l.ch) //&token.Token{Type: token.EXPAND, Literal: literal}
+				tok = l.newToken(token.EXPAND, l.ch, start) //&token.Token{Type: token.EXPAND, Literal: literal}
+				tok.Literal = token.EXPAND
 			} else {
-				tok = l.newToken(token.ILLEGAL, l.ch)
+				tok = l.newToken(token.ILLEGAL, l.ch, start)
+				tok.Literal = ".."
 			}
 		} else {
 			tok = l.newToken(token.ILLEGAL, l.ch)
@@ -73,13 +167,15 @@ func (l *Lexer) NextToken() *token.Token {
 	case '"':
 		if l.peekRune() == '"' {
 			//ch := l.ch
+			start := token.Pos{Line: l.Line, Col: l.Col}
 			l.readRune()
 			if l.peekRune() == '"' {
 				l.readRune()
 				l.del = token.RAWSTRINGDEL
 				tok = l.readString()
 			} else {
-				tok = l.newToken(token.ILLEGAL, l.ch)
+				// empty string
+				tok = &token.Token{Cat: token.VALUE, Type: token.STRING, Literal: "", Loc: start}
 			}
 		} else {
 			l.del = token.STRINGDEL
@@ -112,49 +208,89 @@ func (l *Lexer) NextToken() *token.Token {
 		tok.Cat = token.VALUE                // maybe a VAL when not in Variable def otherwise is an IDENT. Default to a VALUE
 	case '=':
 		tok = l.newToken(token.ASSIGN, l.ch)
+	case '|':
+		tok = l.newToken(token.PIPE, l.ch)
+	case '&':
+		tok = l.newToken(token.AMPERSAND, l.ch)
 	case 0:
 		tok = l.newToken(token.EOF, l.ch)
 		tok.Literal = ""
 		tok.Type = token.EOF
 	default:
-		if unicode.IsLetter(l.ch) || l.ch == '_' {
+		if isNameStart(l.ch) {
 			tok = l.readIdentifier()
 			tok.Type, tok.Cat, tok.IsScalarType = token.LookupIdent(tok.Literal) // IDENT,nil or <keyword>,<VALUE | NONVALUE>
-		} else if unicode.IsDigit(l.ch) || l.ch == '-' {
+		} else if isDigit(l.ch) || l.ch == '-' {
 			tok = l.readNumber()
 		} else {
 			tok = l.newToken(token.ILLEGAL, l.ch)
+			l.readRune() // read over the illegal character so the parser can resume after it
 		}
 		return tok
 	}
-	if tok.Type != "ILLEGAL" {
-		l.readRune() // prime l.ch
-	}
+	l.readRune() // prime l.ch
 	return tok
 }
 
-func (l *Lexer) skipWhitespace() {
-	// Horizontal Tab (U+0009) Space (U+0020)
-	// LineTerminator :: New Line (U+000A)
-	//  Carriage Return (U+000D) [lookahead ≠ New Line (U+000A)] Carriage Return (U+000D) New Line (U+000A)
-	for l.ch == '\u0009' || l.ch == '\u0020' || l.ch == '\u000A' || l.ch == '\u000D' || l.ch == ',' {
-		if l.ch == '\n' { // linefeed
-			l.Line++
-			l.Col = 0
+// readTrivia reads over the white space, commas and comments under examination, stopping at a line terminator
+// when sameLine is set. It returns what it read when the lexer keeps trivia.
+func (l *Lexer) readTrivia(sameLine bool) []token.Trivia {
+	var trivia []token.Trivia
+	for {
+		start, sLoc := token.Pos{Line: l.Line, Col: l.Col}, l.cLoc
+		if l.ch == '\n' || l.ch == '\r' {
+			// the column is not counted until after a line terminator
+			start.Col++
+		}
+		var tt token.TokenType
+		switch l.ch {
+		case ',':
+			tt = token.COMMA
+			l.readRune()
+		case '#':
+			tt = token.COMMENT
+			l.readToEol()
+		case '\u0009', ' ', '\u000A', '\u000D':
+			// Horizontal Tab (U+0009) Space (U+0020)
+			// LineTerminator :: New Line (U+000A)
+			//  Carriage Return (U+000D) [lookahead ≠ New Line (U+000A)] Carriage Return (U+000D) New Line (U+000A)
+			tt = token.WHITESPACE
+			for l.ch == '\u0009' || l.ch == ' ' || !sameLine && (l.ch == '\u000A' || l.ch == '\u000D') {
+				l.lineTerminator()
+				l.readRune()
+			}
+			if l.cLoc == sLoc {
+				// a line terminator ends the trailing trivia
+				return trivia
+			}
+		default:
+			return trivia
+		}
+		if l.trivia {
+			trivia = append(trivia, token.Trivia{Type: tt, Literal: string(l.input[sLoc:l.end()]), Loc: start, Offset: l.base + sLoc})
 		}
-		l.readRune()
+	}
+}
+
+// lineTerminator starts a new line when the rune under examination ends one: a linefeed, or a carriage return
+// not followed by a linefeed.
+func (l *Lexer) lineTerminator() {
+	if l.ch == '\n' || l.ch == '\r' && l.peekRune() != '\n' {
+		l.Line++
+		l.Col = 0
 	}
 }
 
 func (l *Lexer) readRune() {
 	// get next byte in string
+	l.fill(utf8.UTFMax)
 	if l.rLoc >= len(l.input) {
 		l.ch = 0
 		l.cLoc++
 	} else {
 		var size int
 		// TODO: check token type. Only comment and string need rune reads all others simple ascii will suffice
-		l.ch, size = utf8.DecodeRuneInString(l.input[l.rLoc:])
+		l.ch, size = utf8.DecodeRune(l.input[l.rLoc:])
 		l.cLoc = l.rLoc
 		l.rLoc += size
 		if !(l.ch == '\n' || l.ch == '\r') {
@@ -166,10 +302,11 @@ func (l *Lexer) readRune() {
 }
 
 func (l *Lexer) peekRune() rune {
+	l.fill(utf8.UTFMax)
 	if l.rLoc >= len(l.input) {
 		return 0
 	} else {
-		rn, _ := utf8.DecodeRuneInString(l.input[l.rLoc:])
+		rn, _ := utf8.DecodeRune(l.input[l.rLoc:])
 		return rn
 	}
 }
@@ -177,116 +314,302 @@ func (l *Lexer) peekRune() rune {
 func (l *Lexer) readIdentifier() *token.Token {
 	start := token.Pos{l.Line, l.Col}
 	Loc := l.cLoc
-	for unicode.IsLetter(l.ch) || l.ch == '_' || unicode.IsDigit(l.ch) {
+	for isNameStart(l.ch) || isDigit(l.ch) {
 		l.readRune()
 	}
-	return &token.Token{Cat: token.NONVALUE, Type: token.STRING, Literal: l.input[Loc:l.cLoc], Loc: start}
+	return &token.Token{Cat: token.NONVALUE, Type: token.STRING, Literal: string(l.input[Loc:l.cLoc]), Loc: start}
 }
 
+// readNumber reads an IntValue or FloatValue:
+//
+//	IntegerPart :: -? 0 | -? NonZeroDigit Digit*
+//	FractionalPart :: . Digit+
+//	ExponentPart :: (e|E) (+|-)? Digit+
+//
+// neither of which may be followed by a Digit, . or NameStart. A number that breaks the grammar is returned as an
+// ILLEGAL token, covering the run of name and number runes it starts, positioned where it breaks the grammar.
 func (l *Lexer) readNumber() *token.Token {
 	var tokenT token.TokenType = token.INT
-	var illegalT bool
 	sLoc := l.cLoc
-	start := token.Pos{l.Line, l.Col}
+	start := token.Pos{Line: l.Line, Col: l.Col}
 	if l.ch == '-' {
-		//l.skipWhitespace()
 		l.readRune()
 	}
-	for unicode.IsDigit(l.ch) {
+	// digits reads one or more digits
+	digits := func() bool {
+		if !isDigit(l.ch) {
+			return false
+		}
+		for isDigit(l.ch) {
+			l.readRune()
+		}
+		return true
+	}
+	ok := true
+	if l.ch == '0' {
+		// no leading zeros
 		l.readRune()
+	} else {
+		ok = digits()
 	}
-	tokenT = token.INT
-	switch l.ch {
-	case '.':
+	if ok && l.ch == '.' {
+		tokenT = token.FLOAT
+		l.readRune()
+		ok = digits()
+	}
+	if ok && (l.ch == 'e' || l.ch == 'E') {
 		tokenT = token.FLOAT
 		l.readRune()
-		for unicode.IsDigit(l.ch) {
+		if l.ch == '+' || l.ch == '-' {
 			l.readRune()
 		}
-		if l.ch == 'e' || l.ch == 'E' {
-			l.readRune()
-			if l.ch == '-' || l.ch == '+' {
-				l.readRune()
+		ok = digits()
+	}
+	if ok && !(isDigit(l.ch) || l.ch == '.' || isNameStart(l.ch)) {
+		return &token.Token{Cat: token.VALUE, Type: tokenT, Literal: string(l.input[sLoc:l.cLoc]), Loc: start}
+	}
+	illegal := token.Pos{Line: l.Line, Col: l.Col}
+	for isDigit(l.ch) || l.ch == '.' || isNameStart(l.ch) || l.ch == '+' || l.ch == '-' {
+		l.readRune()
+	}
+	return &token.Token{Cat: token.VALUE, Type: token.ILLEGAL, Literal: string(l.input[sLoc:l.end()]), Loc: illegal}
+}
+
+// isNameStart reports whether r may start a Name, [_A-Za-z].
+func isNameStart(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '_'
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func (l *Lexer) readString() *token.Token {
+
+	if l.del == token.STRINGDEL {
+		return l.readStringValue()
+	}
+	return l.readBlockString()
+}
+
+// readStringValue reads a single quoted string starting at the " under examination and returns its decoded value.
+// A string containing an invalid escape sequence is returned as an ILLEGAL token positioned at the sequence,
+// and an unterminated string as an ILLEGAL token positioned at its opening quote.
+func (l *Lexer) readStringValue() *token.Token {
+	var (
+		b       strings.Builder
+		illegal *token.Token
+	)
+	start := token.Pos{Line: l.Line, Col: l.Col}
+	sLoc := l.cLoc
+	for {
+		l.readRune()
+		switch l.ch {
+		case '"':
+			if illegal != nil {
+				return illegal
 			}
-			for unicode.IsDigit(l.ch) {
-				l.readRune()
+			return &token.Token{Cat: token.VALUE, Type: token.STRING, Literal: b.String(), Loc: start}
+
+		case 0, '\n', '\r':
+			// a string must close on the line it starts
+			l.lineTerminator()
+			return &token.Token{Cat: token.VALUE, Type: token.ILLEGAL, Literal: string(l.input[sLoc:l.end()]), Loc: start}
+
+		case '\\':
+			pos, eLoc := token.Pos{Line: l.Line, Col: l.Col}, l.cLoc
+			if !l.readEscape(&b) && illegal == nil {
+				// report the first invalid sequence
+				illegal = &token.Token{Cat: token.VALUE, Type: token.ILLEGAL, Literal: string(l.input[eLoc:l.rLoc]), Loc: pos}
 			}
+
+		default:
+			b.WriteRune(l.ch)
 		}
+	}
+}
 
-	case 'e', 'E':
-		tokenT = token.FLOAT
+// readBlockString reads a block string, the last of its opening """ being under examination, and returns its value
+// as defined by BlockStringValue. Within a block string \""" is an escaped """ and no other escapes are decoded.
+// An unterminated block string is returned as an ILLEGAL token positioned at its opening quotes.
+func (l *Lexer) readBlockString() *token.Token {
+	var raw strings.Builder
+	start := token.Pos{Line: l.Line, Col: l.Col - 2}
+	sLoc := l.cLoc - 2
+	for {
 		l.readRune()
-		if l.ch == '-' || l.ch == '+' {
+		switch {
+		case l.ch == 0 && l.rLoc >= len(l.input):
+			return &token.Token{Cat: token.VALUE, Type: token.ILLEGAL, Literal: string(l.input[sLoc:l.end()]), Loc: start}
+
+		case l.ch == '"' && l.lookingAt(l.cLoc, token.RAWSTRINGDEL):
+			l.readRune()
+			l.readRune() // rest on the last closing quote
+			return &token.Token{Cat: token.VALUE, Type: token.RAWSTRING, Literal: blockStringValue(raw.String()), Loc: start}
+
+		case l.ch == '\\' && l.lookingAt(l.rLoc, token.RAWSTRINGDEL):
 			l.readRune()
-		}
-		for unicode.IsDigit(l.ch) {
 			l.readRune()
+			l.readRune()
+			raw.WriteString(token.RAWSTRINGDEL)
+
+		default:
+			l.lineTerminator()
+			raw.WriteRune(l.ch)
 		}
+	}
+}
 
-	default: // all letters other than e E
-		if unicode.IsLetter(l.ch) {
-			//l.Eloc = token.Pos{l.Line, l.Col}
-			// token is now interpreted as an illegal IDENT
-			illegalT = true
-			tokenT = token.IDENT
-			for !(unicode.IsSpace(l.ch)) {
-				l.readRune()
+// blockStringValue removes the common indentation of the lines after the first in raw, the value of a block string
+// between its quotes, and then its leading and trailing blank lines. Line terminators are normalised to \n.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(raw), "\n")
+	common := -1
+	for _, ln := range lines[1:] {
+		indent := len(ln) - len(strings.TrimLeft(ln, " \t"))
+		if indent < len(ln) && (common < 0 || indent < common) {
+			common = indent
+		}
+	}
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < common {
+				lines[i] = ""
+			} else {
+				lines[i] = lines[i][common:]
 			}
 		}
 	}
-	last := l.cLoc // no digits read after + or -
-	if sLoc == l.cLoc {
-		tokenT = token.IDENT
-		illegalT = true
-		last = l.cLoc + 2 // include rune next to + or -
-		l.readRune()      // read over + -
+	for len(lines) > 0 && strings.Trim(lines[0], " \t") == "" {
+		lines = lines[1:]
 	}
-	return &token.Token{Cat: token.VALUE, Type: tokenT, Literal: l.input[sLoc:last], Illegal: illegalT, Loc: start}
-
+	for len(lines) > 0 && strings.Trim(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
 }
 
-func (l *Lexer) readString() *token.Token {
+// end returns the input offset of the rune under examination, or the length of the input once it is exhausted.
+func (l *Lexer) end() int {
+	if l.ch == 0 && l.rLoc >= len(l.input) {
+		return len(l.input)
+	}
+	return l.cLoc
+}
 
-	Loc := l.cLoc + 1
-	start := token.Pos{l.Line, l.Col}
-	//fmt.Println("Loc: ", Loc)
-	for {
+// readEscape decodes the escape sequence following the \ under examination into b, leaving the lexer on
+// the last rune of the sequence. It reports false, having read up to the rune that made it invalid, when the
+// sequence is not an EscapedCharacter or EscapedUnicode. A \uXXXX leading surrogate must be followed by a \uXXXX trailing surrogate.
+func (l *Lexer) readEscape(b *strings.Builder) bool {
+	switch r := l.peekRune(); r {
+	case '"', '\\', '/':
+		b.WriteRune(r)
+	case 'b':
+		b.WriteRune('\b')
+	case 'f':
+		b.WriteRune('\f')
+	case 'n':
+		b.WriteRune('\n')
+	case 'r':
+		b.WriteRune('\r')
+	case 't':
+		b.WriteRune('\t')
+	case 'u':
 		l.readRune()
-		if l.ch == '"' { // "
-			if l.del == token.STRINGDEL {
-				break
-			} else {
+		if l.peekRune() == '{' {
+			// braced form: \u{1F600}
+			l.readRune()
+			var v rune
+			n := 0
+			for ; isHex(l.peekRune()) && v <= unicode.MaxRune; n++ {
 				l.readRune()
-				if l.ch == '"' { // "
-					if l.del == token.STRINGDEL {
-						return l.newToken(token.ILLEGAL, l.ch)
-					}
-					l.readRune()
-					if l.ch == '"' { // "
-						break
-					}
-				}
-				return l.newToken(token.ILLEGAL, l.ch)
+				v = v<<4 | hexVal(l.ch)
 			}
+			if n == 0 || l.peekRune() != '}' {
+				l.readInvalid()
+				return false
+			}
+			l.readRune() // read over }
+			if v > unicode.MaxRune || utf16.IsSurrogate(v) {
+				return false
+			}
+			b.WriteRune(v)
+			return true
+		}
+		v, ok := l.readHex4()
+		if !ok {
+			l.readInvalid()
+			return false
+		}
+		if !utf16.IsSurrogate(v) {
+			b.WriteRune(v)
+			return true
+		}
+		if v >= 0xDC00 || !l.lookingAt(l.rLoc, `\u`) {
+			// a trailing surrogate, or a leading surrogate on its own
+			return false
+		}
+		l.readRune()
+		l.readRune()
+		t, ok := l.readHex4()
+		if !ok {
+			l.readInvalid()
+			return false
 		}
-		if l.del == token.RAWSTRINGDEL && (l.ch == 10) { // linefeed
-			l.Line++
-			l.Col = 0
+		pair := utf16.DecodeRune(v, t)
+		if pair == unicode.ReplacementChar {
+			return false
 		}
+		b.WriteRune(pair)
+		return true
+	default:
+		l.readInvalid()
+		return false
+	}
+	l.readRune()
+	return true
+}
+
+// readHex4 reads the four hex digits of a fixed width \uXXXX escape following the rune under examination.
+func (l *Lexer) readHex4() (rune, bool) {
+	var v rune
+	for i := 0; i < 4; i++ {
+		if !isHex(l.peekRune()) {
+			return 0, false
+		}
+		l.readRune()
+		v = v<<4 | hexVal(l.ch)
 	}
-	//fmt.Println("l.cLoc: ", l.cLoc)
-	var eLoc int
-	if l.del == token.RAWSTRINGDEL {
-		eLoc = 2
-		return &token.Token{Cat: token.VALUE, Type: token.RAWSTRING, Literal: l.input[Loc : l.cLoc-eLoc], Loc: start}
+	return v, true
+}
+
+// readInvalid reads over the rune that made an escape sequence invalid, unless it ends the string or starts another escape.
+func (l *Lexer) readInvalid() {
+	switch l.peekRune() {
+	case '"', '\\', '\n', '\r', 0:
+	default:
+		l.readRune()
+	}
+}
+
+func isHex(r rune) bool {
+	return '0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F'
+}
+
+func hexVal(r rune) rune {
+	switch {
+	case r >= 'a':
+		return r - 'a' + 10
+	case r >= 'A':
+		return r - 'A' + 10
 	}
-	return &token.Token{Cat: token.VALUE, Type: token.STRING, Literal: l.input[Loc : l.cLoc-eLoc], Loc: start}
+	return r - '0'
 }
 
 func (l *Lexer) readToEol() {
 	for {
 		l.readRune()
-		if l.ch == '\u000D' || l.ch == '\u000A' {
+		if l.ch == '\u000D' || l.ch == '\u000A' || l.end() == len(l.input) {
 			//l.skipWhitespace()
 			break
 		}
@@ -2,7 +2,11 @@ package lexer
 
 import (
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/rosshpayne/graphql/token"
 )
@@ -72,8 +76,8 @@ enum Episode {
 		{token.RBRACE, "}"},
 		{token.TYPE, "type"},
 		{token.IDENT, "Character"},
-		{token.LBRACE, "{"}, //15
-		{token.IDENT, "界"},
+		{token.LBRACE, "{"},  //15
+		{token.ILLEGAL, "界"}, // Names are ASCII
 		{token.COLON, ":"},
 		{token.STRING, "String"},
 		{token.BANG, "!"},
@@ -89,7 +93,10 @@ enum Episode {
 		{token.IDENT, "qName"},
 		{token.LBRACE, "{"}, //30
 		{token.EXPAND, "..."},
-		{token.IDENT, "fri世界endFields"},
+		{token.IDENT, "fri"},
+		{token.ILLEGAL, "世"},
+		{token.ILLEGAL, "界"},
+		{token.IDENT, "endFields"},
 		{token.IDENT, "user"},
 		{token.LPAREN, "("},
 		{token.IDENT, "id"}, //35
@@ -118,7 +125,7 @@ enum Episode {
 		{token.LPAREN, "("},
 		{token.IDENT, "message"},
 		{token.COLON, ":"},
-		{token.RAWSTRING, "\n    Hello,\n      World!\n    Yours,\n      GraphQL.\n"},
+		{token.RAWSTRING, "Hello,\n  World!\nYours,\n  GraphQL."},
 		{token.RPAREN, ")"},
 		{token.RBRACE, "}"},
 		{token.MUTATION, "mutation"},
@@ -127,7 +134,7 @@ enum Episode {
 		{token.LPAREN, "("},
 		{token.IDENT, "message"},
 		{token.COLON, ":"},
-		{token.STRING, `Hello,\n  World!\n\nYours,\n  GraphQL.`},
+		{token.STRING, "Hello,\n  World!\n\nYours,\n  GraphQL."},
 		{token.RPAREN, ")"},
 		{token.RBRACE, "}"},
 		{token.ENUM, "enum"},
@@ -230,3 +237,231 @@ query getZuckProfile($devicePicSize: Int = 1234) {
 		}
 	}
 }
+
+func TestStringEscapes(t *testing.T) {
+
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLoc     token.Pos
+	}{
+		{`"say \"hi\""`, token.STRING, `say "hi"`, token.Pos{Line: 1, Col: 1}},
+		{`""`, token.STRING, "", token.Pos{Line: 1, Col: 1}},
+		{`"a\nb\tc\\d\/e\bf\fg\rh"`, token.STRING, "a\nb\tc\\d/e\bf\fg\rh", token.Pos{Line: 1, Col: 1}},
+		{`"étÉ"`, token.STRING, "étÉ", token.Pos{Line: 1, Col: 1}},
+		{`"😀"`, token.STRING, "😀", token.Pos{Line: 1, Col: 1}},
+		{`"\u{1F600} \u{41}"`, token.STRING, "😀 A", token.Pos{Line: 1, Col: 1}},
+		{`  "日本語"`, token.STRING, "日本語", token.Pos{Line: 1, Col: 3}},
+		{`"bad \q escape"`, token.ILLEGAL, `\q`, token.Pos{Line: 1, Col: 6}},
+		{`"\u12G4"`, token.ILLEGAL, `\u12G`, token.Pos{Line: 1, Col: 2}},
+		{`"ok \u{110000}"`, token.ILLEGAL, `\u{110000}`, token.Pos{Line: 1, Col: 5}},
+		{`"\u{D800}"`, token.ILLEGAL, `\u{D800}`, token.Pos{Line: 1, Col: 2}},
+		{`"\u{}"`, token.ILLEGAL, `\u{}`, token.Pos{Line: 1, Col: 2}},
+		{`"a\uDE00"`, token.ILLEGAL, `\uDE00`, token.Pos{Line: 1, Col: 3}},
+		{`"\uD83Dx"`, token.ILLEGAL, `\uD83D`, token.Pos{Line: 1, Col: 2}},
+		{`"\uD83D\u0041"`, token.ILLEGAL, `\uD83D\u0041`, token.Pos{Line: 1, Col: 2}},
+		{`"\uD83D\uDE00"`, token.STRING, "😀", token.Pos{Line: 1, Col: 1}},
+		{"\n  \"unterminated\n", token.ILLEGAL, `"unterminated`, token.Pos{Line: 2, Col: 3}},
+		{`"\u`, token.ILLEGAL, `"\u`, token.Pos{Line: 1, Col: 1}},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q ", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Loc != tt.expectedLoc {
+			t.Fatalf("tests[%d] - position wrong. expected=%v, got=%v", i, tt.expectedLoc, tok.Loc)
+		}
+		if tok = l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF after string, got=%q %q", i, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestBlockStrings(t *testing.T) {
+
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLoc     token.Pos
+		next            token.Pos // position of the token following the string
+	}{
+		{"\"\"\"\n    Hello,\n      World!\n\n    Yours,\n      GraphQL.\n  \"\"\" x", token.RAWSTRING, "Hello,\n  World!\n\nYours,\n  GraphQL.", token.Pos{Line: 1, Col: 1}, token.Pos{Line: 7, Col: 7}},
+		{"\"\"\"\r\n    Hello,\r\n      World!\r\n\"\"\" x", token.RAWSTRING, "Hello,\n  World!", token.Pos{Line: 1, Col: 1}, token.Pos{Line: 4, Col: 5}},
+		{"  \"\"\"first line\n    second\n  third\"\"\" x", token.RAWSTRING, "first line\n  second\nthird", token.Pos{Line: 1, Col: 3}, token.Pos{Line: 3, Col: 12}},
+		{`"""say \"""hi\""" \n not an escape""" x`, token.RAWSTRING, `say """hi""" \n not an escape`, token.Pos{Line: 1, Col: 1}, token.Pos{Line: 1, Col: 39}},
+		{"\"\"\"\n\t\n  \n\"\"\" x", token.RAWSTRING, "", token.Pos{Line: 1, Col: 1}, token.Pos{Line: 4, Col: 5}},
+		{"\"\"\"a\rb\"\"\" x", token.RAWSTRING, "a\nb", token.Pos{Line: 1, Col: 1}, token.Pos{Line: 2, Col: 6}},
+		{"\"\"\"never closed\n", token.ILLEGAL, "\"\"\"never closed\n", token.Pos{Line: 1, Col: 1}, token.Pos{Line: 2, Col: 0}},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q ", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Loc != tt.expectedLoc {
+			t.Fatalf("tests[%d] - position wrong. expected=%v, got=%v", i, tt.expectedLoc, tok.Loc)
+		}
+		if tok = l.NextToken(); tok.Loc != tt.next {
+			t.Fatalf("tests[%d] - position of next token wrong. expected=%v, got=%v %q", i, tt.next, tok.Loc, tok.Literal)
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+
+	inputs := []string{
+		"query A {\r\n  hero(id: \"\\u00e9\\uD83D\\uDE00\", n: -1.5e3) { ...F }\r\n}\n# comment\nfragment F on Character { name }",
+		"{ doc(text: \"\"\"\n    first\n      second \\\"\"\" 日本語\n  \"\"\") }",
+		"query { bad: 007 \"unterminated\n x }",
+		// tokens longer than the buffer grow it, many short ones compact it
+		"{ doc(text: \"\"\"" + strings.Repeat("日本語 text\n", readSize) + "\"\"\") " + strings.Repeat("name # comment\n", readSize) + "}",
+	}
+	for _, tt := range conformance {
+		inputs = append(inputs, tt.input)
+	}
+	for i, input := range inputs {
+		for j, r := range []io.Reader{strings.NewReader(input), iotest.OneByteReader(strings.NewReader(input)), iotest.HalfReader(strings.NewReader(input))} {
+			l, rl := New(input), NewReader(r)
+			l.SetTrivia(j > 0)
+			rl.SetTrivia(j > 0)
+			for {
+				want, got := *l.NextToken(), *rl.NextToken()
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("inputs[%d] - token wrong. expected=%v, got=%v", i, want, got)
+				}
+				if got.Type == token.EOF {
+					break
+				}
+			}
+			if rl.Err() != nil {
+				t.Fatalf("inputs[%d] - unexpected error %s", i, rl.Err())
+			}
+		}
+	}
+}
+
+func TestNewReaderError(t *testing.T) {
+
+	l := NewReader(io.MultiReader(strings.NewReader("{ hero "), iotest.ErrReader(iotest.ErrTimeout)))
+	for _, expected := range []token.TokenType{token.LBRACE, token.IDENT, token.EOF} {
+		if tok := l.NextToken(); tok.Type != expected {
+			t.Fatalf("tokentype wrong. expected=%q, got=%q", expected, tok.Type)
+		}
+	}
+	if l.Err() != iotest.ErrTimeout {
+		t.Fatalf("expected error %q, got %v", iotest.ErrTimeout, l.Err())
+	}
+}
+
+func TestTrivia(t *testing.T) {
+
+	input := "# leading comment\nquery A { # trailing comment\n  hero(id: 1, name: \"日本\") { name }\r\n\n  # inner comment\n}, # last\n"
+
+	l := New(input)
+	l.SetTrivia(true)
+	var (
+		src  strings.Builder
+		toks []token.Token
+	)
+	for {
+		tok := l.NextToken()
+		toks = append(toks, *tok)
+		for _, tv := range tok.Leading {
+			if input[tv.Offset:tv.Offset+len(tv.Literal)] != tv.Literal {
+				t.Fatalf("trivia offset wrong. expected=%q at %d", tv.Literal, tv.Offset)
+			}
+			src.WriteString(tv.Literal)
+		}
+		src.WriteString(input[tok.Offset:tok.End])
+		for _, tv := range tok.Trailing {
+			src.WriteString(tv.Literal)
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+	// the tokens and their trivia cover the input
+	if src.String() != input {
+		t.Fatalf("source from tokens wrong. expected=%q, got=%q", input, src.String())
+	}
+	trivia := func(tvs []token.Trivia) string {
+		var s []string
+		for _, tv := range tvs {
+			s = append(s, fmt.Sprintf("%s %q %d:%d", tv.Type, tv.Literal, tv.Loc.Line, tv.Loc.Col))
+		}
+		return strings.Join(s, ", ")
+	}
+	tests := []struct {
+		literal  string
+		span     string
+		leading  string
+		trailing string
+	}{
+		{"query", "query", `# "# leading comment" 1:1, WHITESPACE "\n" 1:18`, `WHITESPACE " " 2:6`},
+		{"A", "A", ``, `WHITESPACE " " 2:8`},
+		{"{", "{", ``, `WHITESPACE " " 2:10, # "# trailing comment" 2:11`},
+		{"hero", "hero", `WHITESPACE "\n  " 2:29`, ``},
+		{"(", "(", ``, ``},
+		{"id", "id", ``, ``},
+		{":", ":", ``, `WHITESPACE " " 3:11`},
+		{"1", "1", ``, `, "," 3:13, WHITESPACE " " 3:14`},
+		{"name", "name", ``, ``},
+		{":", ":", ``, `WHITESPACE " " 3:20`},
+		{"日本", `"日本"`, ``, ``},
+		{")", ")", ``, `WHITESPACE " " 3:26`},
+		{"{", "{", ``, `WHITESPACE " " 3:28`},
+		{"name", "name", ``, `WHITESPACE " " 3:33`},
+		{"}", "}", ``, ``},
+		{"}", "}", `WHITESPACE "\r\n\n  " 3:35, # "# inner comment" 5:3, WHITESPACE "\n" 5:18`, `, "," 6:2, WHITESPACE " " 6:3, # "# last" 6:4`},
+		{"", "", `WHITESPACE "\n" 6:10`, ``},
+	}
+	if len(toks) != len(tests) {
+		t.Fatalf("expected %d tokens, got %d", len(tests), len(toks))
+	}
+	for i, tt := range tests {
+		tok := toks[i]
+		if tok.Literal != tt.literal {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.literal, tok.Literal)
+		}
+		if input[tok.Offset:tok.End] != tt.span {
+			t.Errorf("tests[%d] - span wrong. expected=%q, got=%q", i, tt.span, input[tok.Offset:tok.End])
+		}
+		if s := trivia(tok.Leading); s != tt.leading {
+			t.Errorf("tests[%d] - leading trivia wrong. expected=%s, got=%s", i, tt.leading, s)
+		}
+		if s := trivia(tok.Trailing); s != tt.trailing {
+			t.Errorf("tests[%d] - trailing trivia wrong. expected=%s, got=%s", i, tt.trailing, s)
+		}
+	}
+	// tokens unterminated at the end of the input span the rest of it
+	for _, input := range []string{`"abc`, `"""`, `{ a """ b`, "# comment"} {
+		l := New(input)
+		for {
+			tok := l.NextToken()
+			if tok.Offset > tok.End || tok.End > len(input) {
+				t.Fatalf("%q - span wrong. got=%d:%d", input, tok.Offset, tok.End)
+			}
+			if tok.Type == token.EOF {
+				if tok.Offset != len(input) {
+					t.Errorf("%q - EOF offset wrong. expected=%d, got=%d", input, len(input), tok.Offset)
+				}
+				break
+			}
+		}
+	}
+}
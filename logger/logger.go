@@ -0,0 +1,115 @@
+// Package logger defines the structured logger injected into the lexer, parser and server.
+//
+// Messages carry a level and key/value fields, e.g.
+//
+//	log.Log(logger.Debug, "resolver returned", "field", "hero", "bytes", 120)
+//
+// Fields common to a request, such as its ID, are attached once with With. The default
+// logger, Nop, discards everything.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// RequestID is the field key of the request ID.
+const RequestID = "request_id"
+
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// Logger records messages with key/value fields. keyvals alternate between a string key and its value.
+type Logger interface {
+	Log(level Level, msg string, keyvals ...interface{})
+	// With returns a Logger that adds keyvals to every message.
+	With(keyvals ...interface{}) Logger
+}
+
+type nop struct{}
+
+func (nop) Log(Level, string, ...interface{}) {}
+func (n nop) With(...interface{}) Logger      { return n }
+
+// Nop returns a Logger that discards all messages.
+func Nop() Logger {
+	return nop{}
+}
+
+// textLogger writes one line per message: time level msg key=value ...
+type textLogger struct {
+	mu      *sync.Mutex // shared by loggers derived with With, so lines are not interleaved
+	w       io.Writer
+	min     Level
+	keyvals []interface{}
+}
+
+// New returns a Logger that writes messages at min level or above to w as lines of text.
+func New(w io.Writer, min Level) Logger {
+	return &textLogger{mu: &sync.Mutex{}, w: w, min: min}
+}
+
+func (t *textLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	if level < t.min {
+		return
+	}
+	var s strings.Builder
+	s.WriteString(time.Now().Format(time.RFC3339))
+	s.WriteByte(' ')
+	s.WriteString(level.String())
+	s.WriteByte(' ')
+	s.WriteString(msg)
+	writeFields(&s, t.keyvals)
+	writeFields(&s, keyvals)
+	s.WriteByte('\n')
+	t.mu.Lock()
+	io.WriteString(t.w, s.String())
+	t.mu.Unlock()
+}
+
+func (t *textLogger) With(keyvals ...interface{}) Logger {
+	kv := make([]interface{}, 0, len(t.keyvals)+len(keyvals))
+	kv = append(kv, t.keyvals...)
+	kv = append(kv, keyvals...)
+	return &textLogger{mu: t.mu, w: t.w, min: t.min, keyvals: kv}
+}
+
+func writeFields(s *strings.Builder, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		s.WriteByte(' ')
+		fmt.Fprint(s, keyvals[i])
+		s.WriteByte('=')
+		if i+1 < len(keyvals) {
+			v := fmt.Sprint(keyvals[i+1])
+			if strings.ContainsAny(v, " \t\n\"=") {
+				v = fmt.Sprintf("%q", v)
+			}
+			s.WriteString(v)
+		} else {
+			s.WriteString("MISSING")
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextLogger(t *testing.T) {
+
+	var out strings.Builder
+	log := New(&out, Info).With(RequestID, "r1")
+
+	log.Log(Debug, "not written")
+	log.Log(Info, "parsed", "statements", 2, "name", "hero query")
+	log.With("document", "starwars").Log(Error, "failed", "odd")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines got %d: %q", len(lines), out.String())
+	}
+	// strip time
+	for i, l := range lines {
+		lines[i] = l[strings.Index(l, " ")+1:]
+	}
+	expected := []string{
+		`INFO parsed request_id=r1 statements=2 name="hero query"`,
+		`ERROR failed request_id=r1 document=starwars odd=MISSING`,
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Got [%s] expected [%s]", lines[i], e)
+		}
+	}
+}
+
+func TestNop(t *testing.T) {
+	Nop().With("a", 1).Log(Error, "discarded")
+}
@@ -0,0 +1,219 @@
+// Package metrics counts operations and resolver calls and exposes them in the Prometheus text
+// exposition format. Metrics is a tracing.Tracer, so it is assigned to a parser with SetTracer,
+// and an http.Handler, so it is served from the process, typically at /metrics:
+//
+//	m := metrics.New()
+//	reg.SetTracer(m)
+//	http.Handle("/metrics", m)
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rosshpayne/graphql/tracing"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histograms.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics of the documents executed by the parsers it is assigned to. It is safe for concurrent use.
+type Metrics struct {
+	sync.Mutex
+	operations         *counter
+	operationErrors    *counter
+	operationLatency   *histogram
+	validationFailures *counter
+	resolverLatency    *histogram
+	resolverTimeouts   *counter
+	resolverErrors     *counter
+}
+
+func New() *Metrics {
+	return &Metrics{
+		operations:         newCounter("graphql_operations_total", "Operations executed.", "operation", "type"),
+		operationErrors:    newCounter("graphql_operation_errors_total", "Operations that completed with errors.", "operation", "type"),
+		operationLatency:   newHistogram("graphql_operation_duration_seconds", "Latency of operations.", DefaultBuckets, "operation", "type"),
+		validationFailures: newCounter("graphql_validation_failures_total", "Documents that failed validation."),
+		resolverLatency:    newHistogram("graphql_resolver_duration_seconds", "Latency of resolvers by Type.field.", DefaultBuckets, "field"),
+		resolverTimeouts:   newCounter("graphql_resolver_timeouts_total", "Resolvers that did not respond within the resolver timeout.", "field"),
+		resolverErrors:     newCounter("graphql_resolver_errors_total", "Resolvers that timed out, produced no content or returned an unparsable response.", "field"),
+	}
+}
+
+// Start records the span when it finishes. Parse and serialisation spans are not recorded.
+func (m *Metrics) Start(span tracing.Span) func(error) {
+	start := time.Now()
+	return func(err error) {
+		secs := time.Since(start).Seconds()
+		m.Lock()
+		defer m.Unlock()
+		switch span.Kind {
+		case tracing.Validate:
+			if err != nil {
+				m.validationFailures.inc()
+			}
+		case tracing.Operation:
+			m.operations.inc(span.Operation, span.OperationType)
+			m.operationLatency.observe(secs, span.Operation, span.OperationType)
+			if err != nil {
+				m.operationErrors.inc(span.Operation, span.OperationType)
+			}
+		case tracing.Resolve:
+			field := span.ParentType + "." + span.FieldName
+			m.resolverLatency.observe(secs, field)
+			if err != nil {
+				m.resolverErrors.inc(field)
+				if errors.Is(err, context.DeadlineExceeded) {
+					m.resolverTimeouts.inc(field)
+				}
+			}
+		}
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	m.Lock()
+	for _, c := range []*counter{m.operations, m.operationErrors, m.validationFailures, m.resolverTimeouts, m.resolverErrors} {
+		c.write(&b)
+	}
+	for _, h := range []*histogram{m.operationLatency, m.resolverLatency} {
+		h.write(&b)
+	}
+	m.Unlock()
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics to a Prometheus scrape.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// series are the values of a metric keyed by their label values, joined by labelSep.
+const labelSep = "\xff"
+
+type counter struct {
+	name, help string
+	labels     []string
+	values     map[string]float64
+}
+
+func newCounter(name, help string, labels ...string) *counter {
+	return &counter{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+func (c *counter) inc(labelValues ...string) {
+	c.values[strings.Join(labelValues, labelSep)]++
+}
+
+func (c *counter) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		// an unlabelled counter is reported from zero
+		fmt.Fprintf(b, "%s 0\n", c.name)
+	}
+	for _, k := range sortedKeys(c.values) {
+		fmt.Fprintf(b, "%s%s %s\n", c.name, labelPairs(c.labels, k), formatFloat(c.values[k]))
+	}
+}
+
+type histogram struct {
+	name, help string
+	labels     []string
+	leLabels   []string // labels of a bucket, suffixed by le
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	counts []uint64 // cumulative, by bucket
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(name, help string, buckets []float64, labels ...string) *histogram {
+	leLabels := append(append([]string{}, labels...), "le")
+	return &histogram{name: name, help: help, labels: labels, leLabels: leLabels, buckets: buckets, series: make(map[string]*series)}
+}
+
+func (h *histogram) observe(v float64, labelValues ...string) {
+	k := strings.Join(labelValues, labelSep)
+	s, ok := h.series[k]
+	if !ok {
+		s = &series{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+	for i, ub := range h.buckets {
+		if v <= ub {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *histogram) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		for i, ub := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, labelPairs(h.leLabels, k+labelSep+formatFloat(ub)), s.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, labelPairs(h.leLabels, k+labelSep+"+Inf"), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", h.name, labelPairs(h.labels, k), formatFloat(s.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", h.name, labelPairs(h.labels, k), s.count)
+	}
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelPairs formats the label names with the values of series key k, e.g. {operation="Q",type="query"}.
+func labelPairs(names []string, k string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	values := strings.Split(k, labelSep)
+	pairs := make([]string, len(names))
+	for i, n := range names {
+		var v string
+		if i < len(values) {
+			v = values[i]
+		}
+		pairs[i] = n + `="` + escape(v) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
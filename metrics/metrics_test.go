@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rosshpayne/graphql/tracing"
+)
+
+func TestMetrics(t *testing.T) {
+
+	m := New()
+	m.Start(tracing.Span{Kind: tracing.Validate})(errors.New(`Field "x" is not defined`))
+	m.Start(tracing.Span{Kind: tracing.Validate})(nil)
+	m.Start(tracing.Span{Kind: tracing.Operation, Operation: "Q", OperationType: "query"})(nil)
+	m.Start(tracing.Span{Kind: tracing.Operation, Operation: "Q", OperationType: "query"})(errors.New("failed"))
+	hero := tracing.Span{Kind: tracing.Resolve, ParentType: "Query", FieldName: "hero"}
+	m.Start(hero)(nil)
+	m.Start(hero)(fmt.Errorf("Resolver timed out: %w", context.DeadlineExceeded))
+	m.Start(hero)(errors.New("Resolver produced no content"))
+	m.Start(tracing.Span{Kind: tracing.Resolve, ParentType: `Odd"Type`, FieldName: "f"})(nil)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %s", ct)
+	}
+	out := w.Body.String()
+	for _, v := range []string{
+		"# TYPE graphql_operations_total counter",
+		`graphql_operations_total{operation="Q",type="query"} 2`,
+		`graphql_operation_errors_total{operation="Q",type="query"} 1`,
+		"graphql_validation_failures_total 1",
+		"# TYPE graphql_resolver_duration_seconds histogram",
+		`graphql_resolver_duration_seconds_bucket{field="Query.hero",le="+Inf"} 3`,
+		`graphql_resolver_duration_seconds_count{field="Query.hero"} 3`,
+		`graphql_resolver_duration_seconds_count{field="Odd\"Type.f"} 1`,
+		`graphql_resolver_timeouts_total{field="Query.hero"} 1`,
+		`graphql_resolver_errors_total{field="Query.hero"} 2`,
+		`graphql_operation_duration_seconds_bucket{operation="Q",type="query",le="0.005"}`,
+	} {
+		if !strings.Contains(out, v) {
+			t.Errorf("Expected %s in:\n%s", v, out)
+		}
+	}
+}
+
+func TestMetricsEmpty(t *testing.T) {
+	var out strings.Builder
+	New().WriteTo(&out)
+	if !strings.Contains(out.String(), "graphql_validation_failures_total 0\n") {
+		t.Errorf("Expected zero validation failures in:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "graphql_operations_total{") {
+		t.Errorf("Expected no operation series in:\n%s", out.String())
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/auth"
+	"github.com/rosshpayne/graphql/logger"
+)
+
+// FieldError is an error in the resolution of a field that does not abort the statement.
+// The field is null in the response, which reports the error in its errors member.
+type FieldError struct {
+	Message string
+	Path    []interface{} // response path of the field, field names and list indexes e.g. [hero 0 name]
+	Code    string        // e.g. auth.Forbidden
+}
+
+func (e *FieldError) Error() string {
+	path := make([]string, len(e.Path))
+	for i, v := range e.Path {
+		path[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%s at %s", e.Message, strings.Join(path, "/"))
+}
+
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	type extensions struct {
+		Code string `json:"code,omitempty"`
+	}
+	return json.Marshal(struct {
+		Message    string        `json:"message"`
+		Path       []interface{} `json:"path"`
+		Extensions extensions    `json:"extensions"`
+	}{e.Message, e.Path, extensions{e.Code}})
+}
+
+// SetContext assigns the context of the document, which carries request values such as the principal
+// authorised by the @auth directive. It is the parent of the context passed to middleware and resolvers.
+func (p *Parser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// SetRejectForbidden determines whether validation rejects an operation that selects only fields
+// denied to the principal of the parser's context. By default such fields are null in the response.
+func (p *Parser) SetRejectForbidden(reject bool) {
+	p.rejectForbidden = reject
+}
+
+// addFieldErr records a field error against the response path of the field.
+func (p *Parser) addFieldErr(path []interface{}, code string, err error) {
+	fe := &FieldError{Message: err.Error(), Path: path, Code: code}
+	p.perrorMx.Lock()
+	p.fieldErrs = append(p.fieldErrs, fe)
+	p.perrorMx.Unlock()
+}
+
+// addFieldErrors appends the field errors to the response.
+func (p *Parser) addFieldErrors(resultJson string) string {
+	if len(p.fieldErrs) == 0 {
+		return resultJson
+	}
+	b, err := json.Marshal(p.fieldErrs)
+	if err != nil {
+		p.log.Log(logger.Warn, "marshal field errors", "error", err)
+		return resultJson
+	}
+	i := strings.LastIndex(resultJson, "}")
+	if i < 0 {
+		return resultJson
+	}
+	return resultJson[:i] + ",\nerrors: " + string(b) + "\n" + resultJson[i:]
+}
+
+// checkForbidden reports an error if the principal may not access any field the operation selects.
+func (p *Parser) checkForbidden(stmt *ast.OperationStmt) {
+	if !p.selectsAuthorised(stmt.SelectionSet) {
+		p.addErr(fmt.Sprintf(`%s: operation "%s" selects only fields the principal is not authorised to access`, auth.Forbidden, operationName(stmt.Name.String())))
+	}
+}
+
+// selectsAuthorised reports whether the principal may access at least one field of set,
+// a field of an object type being accessible only if one of its own fields is.
+func (p *Parser) selectsAuthorised(set []ast.SelectionSetProvider) bool {
+	for _, v := range set {
+		switch x := v.(type) {
+		case *ast.Field:
+			if x.SDLfld == nil {
+				return true
+			}
+			if auth.Authorize(p.ctx, parentTypeName(x), x.SDLfld) == nil && (len(x.SelectionSet) == 0 || p.selectsAuthorised(x.SelectionSet)) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if p.selectsAuthorised(x.SelectionSet) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if x.FragStmt != nil && p.selectsAuthorised(x.FragStmt.SelectionSet) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rosshpayne/graphql/auth"
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+var authSDL = auth.Directive + `
+	schema {
+		query : Query
+	}
+	type Query { hero(episode: Episode): [Character] }
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	type Character {
+		id: String!
+		name: String! @auth(requires: ["reader", "admin"])
+		totalCredits: Int @auth(requires: ["admin"])
+	}`
+
+func runAuth(t *testing.T, s *Schema, roles []string, reject bool, query string) (string, []error) {
+	p := NewWithSchema(lexer.New(query), s)
+	if roles != nil {
+		p.SetContext(auth.WithPrincipal(context.Background(), &auth.Principal{ID: "u1", Roles: roles}))
+	}
+	p.SetRejectForbidden(reject)
+	p.Resolver.Register("Query/hero", resolveHeroes)
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		return "", errs
+	}
+	return p.ExecuteDocument()
+}
+
+func TestAuthDirective(t *testing.T) {
+
+	s, errs := NewSchema(authSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	query := `query { hero(episode: JEDI) { id name totalCredits } }`
+
+	result, errs := runAuth(t, s, []string{"admin"}, false, query)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors for admin: %v", errs)
+	}
+	if !strings.Contains(trimWS(result), `name:"Luke"totalCredits:10`) {
+		t.Errorf("Unexpected result for admin: %s", result)
+	}
+
+	// a reader may see names but not credits, which are null
+	result, errs = runAuth(t, s, []string{"reader"}, false, query)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 field errors for reader, got %v", errs)
+	}
+	// hero is a list, so the path of each denied field includes the index of its item
+	for i, path := range []string{"[hero 0 totalCredits]", "[hero 1 totalCredits]"} {
+		var fe *FieldError
+		if !errors.As(errs[i], &fe) || fe.Code != auth.Forbidden || fmt.Sprint(fe.Path) != path {
+			t.Errorf("Unexpected field error %#v", errs[i])
+		}
+	}
+	if r := trimWS(result); !strings.Contains(r, `name:"Luke"totalCredits:null`) || !strings.Contains(r, `name:"Leia"totalCredits:null`) {
+		t.Errorf("Expected null credits for reader: %s", result)
+	}
+	if !strings.Contains(result, `errors: [{"message":"FORBIDDEN: field \"totalCredits\" of type \"Character\" requires role admin","path":["hero",0,"totalCredits"],"extensions":{"code":"FORBIDDEN"}}`) {
+		t.Errorf("Expected errors member in response: %s", result)
+	}
+
+	// without a principal, protected fields are null. name may not be null, so each hero is null.
+	result, errs = runAuth(t, s, nil, false, query)
+	if len(errs) != 2 || !strings.Contains(trimWS(result), `hero:[nullnull]`) {
+		t.Errorf("Unexpected result without principal: %s %v", result, errs)
+	}
+}
+
+func TestAuthNullPropagation(t *testing.T) {
+
+	query := `query { hero(episode: JEDI) { id name } }`
+	for _, v := range []struct {
+		hero   string
+		result string
+	}{
+		{"[Character]", `{data:{hero:[nullnull]}`},
+		// a denied field makes its nearest nullable parent null
+		{"[Character!]", `{data:{hero:null}`},
+		{"[Character!]!", `{data:null`},
+	} {
+		sdlText := strings.Replace(authSDL, "hero(episode: Episode): [Character]", "hero(episode: Episode): "+v.hero, 1)
+		s, errs := NewSchema(sdlText)
+		for _, e := range errs {
+			t.Fatal(e)
+		}
+		result, errs := runAuth(t, s, nil, false, query)
+		if len(errs) == 0 {
+			t.Errorf("%s: expected field errors", v.hero)
+		}
+		for _, e := range errs {
+			var fe *FieldError
+			if !errors.As(e, &fe) || fe.Code != auth.Forbidden {
+				t.Errorf("%s: unexpected error %s", v.hero, e)
+			}
+		}
+		if got := trimWS(result); !strings.HasPrefix(got, v.result) {
+			t.Errorf("%s: got %s expected %s", v.hero, got, v.result)
+		}
+	}
+}
+
+func TestAuthRejectForbidden(t *testing.T) {
+
+	s, errs := NewSchema(authSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	// only forbidden fields are selected
+	_, errs = runAuth(t, s, []string{"reader"}, true, `query Credits { hero(episode: JEDI) { totalCredits } }`)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `FORBIDDEN: operation "Credits" selects only fields`) {
+		t.Errorf("Expected operation to be rejected, got %v", errs)
+	}
+	// an accessible field is selected
+	result, errs := runAuth(t, s, []string{"reader"}, true, `query Credits { hero(episode: JEDI) { name totalCredits } }`)
+	if len(errs) != 2 || !strings.Contains(result, `"Leia"`) {
+		t.Errorf("Expected result with field errors, got %s %v", result, errs)
+	}
+	// not rejected unless requested
+	_, errs = runAuth(t, s, []string{"reader"}, false, `query Credits { hero(episode: JEDI) { totalCredits } }`)
+	if len(errs) != 2 {
+		t.Errorf("Expected field errors, got %v", errs)
+	}
+}
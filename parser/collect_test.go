@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const collectSDL = `
+	schema {
+		query : Query
+	}
+	type Query { hero(episode: Episode): [Character] search(name: String = "Falcon"): [SearchResult] }
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+		friends: [Character]
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+		friends: [Character]
+		totalCredits: Int
+	}
+	type Starship {
+		id: String!
+		name: String!
+	}
+	union SearchResult = Human | Starship`
+
+var resolveFriends = func(ctx context.Context, resp sdl.InputValueProvider, args sdl.ObjectVals) <-chan string {
+	gql := make(chan string, 1)
+	gql <- `{Human: [{id: "1", name: "Luke", totalCredits: 10, friends: [{id: "2", name: "Leia"} {id: "3", name: "Han"}]}] }`
+	return gql
+}
+
+var resolveSearch = func(ctx context.Context, resp sdl.InputValueProvider, args sdl.ObjectVals) <-chan string {
+	gql := make(chan string, 1)
+	gql <- `{Starship: [{id: "S1", name: "Falcon"}] }`
+	return gql
+}
+
+func TestCollectFields(t *testing.T) {
+
+	s, errs := NewSchema(collectSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input  string
+		result string
+	}{
+		{
+			input: `query { hero(episode: JEDI) { id friends { name } ...heroFields } }
+			        fragment heroFields on Character { id friends { id } }`,
+			result: `{data: {hero: [{id: "1" friends: [{name: "Leia" id: "2"} {name: "Han" id: "3"}]}]}}`,
+		},
+		{
+			input:  `query { hero(episode: JEDI) { name ... on Human { totalCredits name } id } }`,
+			result: `{data: {hero: [{name: "Luke" totalCredits: 10 id: "1"}]}}`,
+		},
+		{
+			// a fragment on an interface the response type does not implement does not apply
+			input: `query { search { ...characterFields ... on Starship { id } } }
+			        fragment characterFields on Character { name }`,
+			result: `{data: {search: [{id: "S1"}]}}`,
+		},
+		{
+			// root fields of the same response key are merged
+			input:  `query { hero(episode: JEDI) { id } hero(episode: JEDI) { name } }`,
+			result: `{data: {hero: [{id: "1" name: "Luke"}]}}`,
+		},
+		{
+			input: `query { ...roots hero(episode: JEDI) { id } ... on Query { search { ...results } } }
+			        fragment roots on Query { hero(episode: JEDI) { name } }
+			        fragment results on SearchResult { ... on Starship { name } }`,
+			result: `{data: [hero: [{name: "Luke" id: "1"}] search: [{name: "Falcon"}]]}`,
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		p.Resolver.Register("Query/hero", resolveFriends)
+		p.Resolver.Register("Query/search", resolveSearch)
+		if _, errs := p.ParseDocument(); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		result, errs := p.ExecuteDocument()
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if compare(result, v.result) {
+			t.Errorf("Got:      [%s] \n", trimWS(result))
+			t.Errorf("Expected: [%s] \n", trimWS(v.result))
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/store"
+)
+
+// run with: go test -race -run TestConcurrentDocuments
+
+const concurrentSDL = `
+	schema {
+		query : Query
+	}
+	type Query { hero(episode: Episode): [Character] }
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+		totalCredits: Int
+	}`
+
+var concurrentStore = store.NewMemory()
+
+// loadSchema adds the types of inputSDL to the in-memory store of the concurrent tests.
+func loadSchema(inputSDL string, t *testing.T) {
+	for _, v := range concurrentStore.AddSDL(defaultDoc, inputSDL) {
+		t.Fatalf("Setup failed for %s: %s", t.Name(), v)
+	}
+}
+
+var resolveHeroes = func(ctx context.Context, resp sdl.InputValueProvider, args sdl.ObjectVals) <-chan string {
+	var episode string
+	for _, v := range args {
+		if v.Name_.EqualString("episode") {
+			if x, ok := v.Value.InputValueProvider.(*sdl.EnumValue_); ok {
+				episode = x.String()
+			}
+		}
+	}
+	gql := make(chan string)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case gql <- fmt.Sprintf(`{Human: [{id: "%s-1", name: "Luke", totalCredits: 10} {id: "%s-2", name: "Leia", totalCredits: 20}] }`, episode, episode):
+		}
+	}()
+	return gql
+}
+
+// concurrentDocs share statement and fragment names, so any per document state held outside the
+// parser corrupts the result of another document.
+var concurrentDocs = []string{
+	`query Q { hero(episode: NEWHOPE) { ...heroFields } }
+	 fragment heroFields on Character { id }`,
+	`query Q { hero(episode: EMPIRE) { ...heroFields } }
+	 fragment heroFields on Character { name }`,
+	`query Q { hero(episode: JEDI) { ...heroFields heroId: id } }
+	 fragment heroFields on Human { totalCredits }`,
+	`query Q { hero(episode: JEDI) { id } }
+	 query Q { hero(episode: JEDI) { name } }`,
+}
+
+func runDocument(input string) (string, []error) {
+	p := NewWithStore(lexer.New(input), concurrentStore)
+	if err := p.Resolver.Register("Query/hero", resolveHeroes); err != nil {
+		return "", []error{err}
+	}
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		return "", errs
+	}
+	return p.ExecuteDocument()
+}
+
+func errString(errs []error) string {
+	var s strings.Builder
+	for _, e := range errs {
+		s.WriteString(e.Error())
+		s.WriteString(";")
+	}
+	return s.String()
+}
+
+func TestConcurrentDocuments(t *testing.T) {
+
+	loadSchema(concurrentSDL, t)
+	//
+	// expected results are generated serially
+	//
+	type result struct {
+		out  string
+		errs string
+	}
+	expected := make([]result, len(concurrentDocs))
+	for i, doc := range concurrentDocs {
+		out, errs := runDocument(doc)
+		expected[i] = result{trimWS(out), errString(errs)}
+	}
+	if expected[3].errs == "" {
+		t.Fatalf("Expected duplicate statement error for document 3")
+	}
+	for i := 0; i < 3; i++ {
+		if len(expected[i].errs) > 0 {
+			t.Fatalf("Unexpected error for document %d: %s", i, expected[i].errs)
+		}
+	}
+
+	const repeat = 25
+	var wg sync.WaitGroup
+	got := make([]result, repeat*len(concurrentDocs))
+	for r := 0; r < repeat; r++ {
+		for i, doc := range concurrentDocs {
+			wg.Add(1)
+			go func(n int, doc string) {
+				defer wg.Done()
+				out, errs := runDocument(doc)
+				got[n] = result{trimWS(out), errString(errs)}
+			}(r*len(concurrentDocs)+i, doc)
+		}
+	}
+	wg.Wait()
+
+	for n, g := range got {
+		i := n % len(concurrentDocs)
+		if g != expected[i] {
+			t.Errorf("Document %d run %d: got [%s] [%s], expected [%s] [%s]", i, n/len(concurrentDocs), g.out, g.errs, expected[i].out, expected[i].errs)
+		}
+	}
+}
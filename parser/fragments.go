@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/ast"
+)
+
+// fragmentSpreads returns the fragment spreads of a selection set, including those of nested fields
+// and inline fragments but not those of the fragments spread.
+func fragmentSpreads(set []ast.SelectionSetProvider) []*ast.FragmentSpread {
+	var spreads []*ast.FragmentSpread
+	for _, s := range set {
+		switch x := s.(type) {
+		case *ast.Field:
+			spreads = append(spreads, fragmentSpreads(x.SelectionSet)...)
+		case *ast.InlineFragment:
+			spreads = append(spreads, fragmentSpreads(x.SelectionSet)...)
+		case *ast.FragmentSpread:
+			spreads = append(spreads, x)
+		}
+	}
+	return spreads
+}
+
+// checkFragmentCycles reports fragments that spread themselves, directly or through other fragments.
+// Validation follows fragment spreads, so it must not proceed when a cycle exists.
+func (p *Parser) checkFragmentCycles(api *ast.Document) {
+
+	var (
+		visited = make(map[sdl.NameValue_]bool)
+		onPath  = make(map[sdl.NameValue_]int) // index into path of the spread of each fragment being followed
+		path    []*ast.FragmentSpread
+		detect  func(frag *ast.FragmentStmt)
+	)
+	detect = func(frag *ast.FragmentStmt) {
+		visited[frag.Name.Name] = true
+		onPath[frag.Name.Name] = len(path)
+		for _, spread := range fragmentSpreads(frag.SelectionSet) {
+			i, cycle := onPath[spread.Name]
+			path = append(path, spread)
+			if !cycle {
+				if next, ok := p.fragmentStmts[spread.Name]; ok && !visited[spread.Name] {
+					detect(next)
+				}
+			} else {
+				var via []string
+				for _, v := range path[i : len(path)-1] {
+					via = append(via, fmt.Sprintf("%q", v.Name))
+				}
+				if len(via) == 0 {
+					p.addErr(fmt.Sprintf(`Cannot spread fragment "%s" within itself %s`, spread.Name, spread.Name_.AtPosition()))
+				} else {
+					p.addErr(fmt.Sprintf(`Cannot spread fragment "%s" within itself via %s %s`, spread.Name, strings.Join(via, ", "), spread.Name_.AtPosition()))
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		delete(onPath, frag.Name.Name)
+	}
+	for _, stmt := range api.Statements {
+		if frag, ok := stmt.AST.(*ast.FragmentStmt); ok && !visited[frag.Name.Name] {
+			detect(frag)
+		}
+	}
+}
+
+// SetSharedFragments determines whether the document's fragments may be spread by operations of other
+// documents, e.g. of other files of a project, in which case fragments are not reported when unused.
+func (p *Parser) SetSharedFragments(shared bool) {
+	p.sharedFragments = shared
+}
+
+// checkUnusedFragments reports fragments that are not spread by an operation, directly or through other fragments.
+func (p *Parser) checkUnusedFragments(api *ast.Document) {
+
+	used := make(map[sdl.NameValue_]bool)
+	var use func(set []ast.SelectionSetProvider)
+	use = func(set []ast.SelectionSetProvider) {
+		for _, spread := range fragmentSpreads(set) {
+			if used[spread.Name] {
+				continue
+			}
+			used[spread.Name] = true
+			if frag, ok := p.fragmentStmts[spread.Name]; ok {
+				use(frag.SelectionSet)
+			}
+		}
+	}
+	for _, stmt := range api.Statements {
+		if op, ok := stmt.AST.(*ast.OperationStmt); ok {
+			use(op.SelectionSet)
+		}
+	}
+	for _, stmt := range api.Statements {
+		if frag, ok := stmt.AST.(*ast.FragmentStmt); ok && !used[frag.Name.Name] {
+			p.addErr(fmt.Sprintf(`Fragment "%s" is not used %s`, frag.Name, frag.Name.AtPosition()))
+		}
+	}
+}
+
+// checkSpreadPossible reports a spread of fragment frag, whose type condition is fragType, within
+// a selection set of type parent when no object can be of both types.
+func (p *Parser) checkSpreadPossible(frag *ast.FragmentSpread, parent, fragType sdl.GQLTypeProvider) {
+	if parent == nil || fragType == nil || p.spreadPossible(parent, fragType) {
+		return
+	}
+	p.addErr(fmt.Sprintf(`Fragment "%s" cannot be spread here as objects of type "%s" can never be of type "%s" %s`, frag.Name, parent.TypeName(), fragType.TypeName(), frag.Name_.AtPosition()))
+}
+
+// checkInlinePossible reports an inline fragment, whose type condition is fragType, within a selection set
+// of type parent when no object can be of both types. It reports whether the fragment is possible.
+func (p *Parser) checkInlinePossible(frag *ast.InlineFragment, parent, fragType sdl.GQLTypeProvider) bool {
+	if parent == nil || fragType == nil || p.spreadPossible(parent, fragType) {
+		return true
+	}
+	if _, ok := fragType.(*sdl.Object_); ok {
+		switch parent.(type) {
+		case *sdl.Union_:
+			p.addErr(fmt.Sprintf(`%q on condition type not a member of union type, "%s" %s`, frag.TypeCond.String(), parent.TypeName(), frag.Name_.AtPosition()))
+			return false
+		case *sdl.Interface_:
+			p.addErr(fmt.Sprintf(`On condition type %q does not implement interface %q, %s`, frag.TypeCond.String(), parent.TypeName(), frag.Name_.AtPosition()))
+			return false
+		case *sdl.Object_:
+			p.addErr(fmt.Sprintf(`Enclosing type for an inline fragment field must be an Interface or Union if type on-condition specified or Object type if none. Got %q %s`, fragType.Type(), frag.Name_.AtPosition()))
+			return false
+		}
+	}
+	p.addErr(fmt.Sprintf(`Fragment cannot be spread here as objects of type "%s" can never be of type "%s" %s`, parent.TypeName(), fragType.TypeName(), frag.Name_.AtPosition()))
+	return false
+}
+
+// spreadPossible reports whether the possible object types of a and b intersect.
+func (p *Parser) spreadPossible(a, b sdl.GQLTypeProvider) bool {
+	at, aok := p.possibleTypes(a)
+	bt, bok := p.possibleTypes(b)
+	switch {
+	case aok && bok:
+		for _, o := range at {
+			for _, v := range bt {
+				if o.Name_.Equals(v.Name_) {
+					return true
+				}
+			}
+		}
+		return false
+	case aok:
+		return implementedBy(b, at)
+	case bok:
+		return implementedBy(a, bt)
+	}
+	// the implementations of neither interface are known
+	return true
+}
+
+// possibleTypes returns the object types a value of type t may be. ok is false for an interface when
+// the type cache holds only the types sourced so far rather than every type of the document.
+func (p *Parser) possibleTypes(t sdl.GQLTypeProvider) (types []*sdl.Object_, ok bool) {
+	switch x := t.(type) {
+	case *sdl.Object_:
+		return []*sdl.Object_{x}, true
+	case *sdl.Union_:
+		for _, v := range x.NameS {
+			if o, err := p.tyCache.FetchAST(v.Name, p.log); err == nil {
+				if o, ok := o.(*sdl.Object_); ok {
+					types = append(types, o)
+				}
+			}
+		}
+		return types, true
+	case *sdl.Interface_:
+		return p.tyCache.implementations(x.Name)
+	}
+	return nil, true
+}
+
+// implementedBy reports whether any of types implements interface t.
+func implementedBy(t sdl.GQLTypeProvider, types []*sdl.Object_) bool {
+	for _, o := range types {
+		for _, v := range o.Implements {
+			if v.EqualString(t.TypeName().String()) {
+				return true
+			}
+		}
+	}
+	return false
+}
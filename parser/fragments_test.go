@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const fragmentsSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		hero(episode: Episode = JEDI): [Character]
+		search: [SearchResult]
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+	}
+	interface Pilot {
+		ship: String
+	}
+	interface Vehicle {
+		model: String
+	}
+	type Human implements Character & Pilot {
+		id: String!
+		name: String!
+		ship: String
+	}
+	type Droid implements Character {
+		id: String!
+		name: String!
+		primaryFunction: String
+	}
+	type Starship {
+		name: String!
+	}
+	union SearchResult = Human | Starship
+	union Fleet = Starship`
+
+func TestFragmentValidation(t *testing.T) {
+
+	s, errs := NewSchema(fragmentsSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `query { hero { ...names } }
+			        fragment names on Character { id ...names }`,
+			errs: []string{`Cannot spread fragment "names" within itself at line: 2 column: 48`},
+		},
+		{
+			input: `query { hero { ...names } }
+			        fragment names on Character { id ...ids }
+			        fragment ids on Character { name ... on Human { ...names } }`,
+			errs: []string{`Cannot spread fragment "names" within itself via "ids" at line: 3 column: 63`},
+		},
+		{
+			input: `query { hero { id } }
+			        fragment names on Character { name }`,
+			errs: []string{`Fragment "names" is not used at line: 2 column: 21`},
+		},
+		{ // interface within interface
+			input: `query { hero { ...pilot } }
+			        fragment pilot on Pilot { ship }`,
+		},
+		{
+			input: `query { hero { ...vehicle } }
+			        fragment vehicle on Vehicle { model }`,
+			errs: []string{`Fragment "vehicle" cannot be spread here as objects of type "Character" can never be of type "Vehicle" at line: 1 column: 19`},
+		},
+		{ // union within interface
+			input: `query { hero { ...result } }
+			        fragment result on SearchResult { ... on Human { id } }`,
+		},
+		{
+			input: `query { hero { ...fleet } }
+			        fragment fleet on Fleet { ... on Starship { name } }`,
+			errs: []string{`Fragment "fleet" cannot be spread here as objects of type "Character" can never be of type "Fleet" at line: 1 column: 19`},
+		},
+		{ // object and interface within union
+			input: `query { search { ...human ...character } }
+			        fragment human on Human { ship }
+			        fragment character on Character { name }`,
+		},
+		{
+			input: `query { search { ...droid } }
+			        fragment droid on Droid { primaryFunction }`,
+			errs: []string{`Fragment "droid" cannot be spread here as objects of type "SearchResult" can never be of type "Droid" at line: 1 column: 21`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
+
+func TestInlineFragmentValidation(t *testing.T) {
+
+	s, errs := NewSchema(fragmentsSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{ // interface within union
+			input: `query { search { ... on Character { name } } }`,
+		},
+		{ // interface within interface
+			input: `query { hero { ... on Pilot { ship } } }`,
+		},
+		{ // union within interface and object
+			input: `query { hero { ... on SearchResult { ... on Human { id } } ... on Human { ... on SearchResult { ... on Human { ship } } } } }`,
+		},
+		{
+			input: `query { hero { ... on Vehicle { model } } }`,
+			errs:  []string{`Fragment cannot be spread here as objects of type "Character" can never be of type "Vehicle" at line: 1 column: 16`},
+		},
+		{
+			input: `query { hero { ... on Human { ... on Fleet { ... on Starship { name } } } } }`,
+			errs:  []string{`Fragment cannot be spread here as objects of type "Human" can never be of type "Fleet" at line: 1 column: 31`},
+		},
+		{
+			input: `query { search { ... on Droid { id } } }`,
+			errs:  []string{`"Droid" on condition type not a member of union type, "SearchResult" at line: 1 column: 18`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
+
+func TestSharedFragments(t *testing.T) {
+
+	s, errs := NewSchema(fragmentsSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	// fragments of a shared document are validated, but may be spread by other documents
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `fragment names on Character { id name }`,
+		},
+		{
+			input: `fragment names on Character { id age }`,
+			errs:  []string{`Field "age" is not a member of "Character" at line: 1 column: 34`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		p.SetSharedFragments(true)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"fmt"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+)
+
+// inputType returns the SDL input definition of t, or nil when t is not an input object type.
+func (p *Parser) inputType(t *sdl.GQLtype) *sdl.Input_ {
+	ast_ := t.AST
+	if ast_ == nil {
+		if t.IsScalar() {
+			return nil
+		}
+		ast_, _ = p.tyCache.FetchAST(t.Name, p.log)
+	}
+	in, _ := ast_.(*sdl.Input_)
+	return in
+}
+
+// checkInputValue validates v, the value of argument or input field nm, against t and coerces it to t.
+// Input object values are checked field by field against the SDL input definition, including the values
+// of nested input objects and lists, and are assigned the default value of each input field not given.
+// Scalar and enum values are validated by the SDL package. A variable without a value is not
+// checked here: one that may not be null is reported by checkVariableValues when its operation is executed.
+func (p *Parser) checkInputValue(v *sdl.InputValue_, t *sdl.GQLtype, nm sdl.Name_) {
+	if v == nil || t == nil {
+		return
+	}
+	if u, ok := p.varUses[v]; ok && u.unbound {
+		return
+	}
+	in := p.inputType(t)
+	if in == nil {
+		if !p.hasUnboundVar(v) {
+			v.CheckInputValueType(t, nm, &p.perror)
+		}
+		return
+	}
+	if _, ok := v.InputValueProvider.(sdl.Null_); ok {
+		v.CheckInputValueType(t, nm, &p.perror)
+		return
+	}
+	if t.Depth > 0 {
+		// item type drops the outermost list and its non-null constraint
+		item := &sdl.GQLtype{Constraint: t.Constraint &^ (1 << t.Depth), AST: in, Depth: t.Depth - 1, Name_: t.Name_, Base: t.Base}
+		l, ok := v.InputValueProvider.(sdl.List_)
+		if !ok {
+			// a single value is coerced to a list of one item
+			l = sdl.List_{&sdl.InputValue_{InputValueProvider: v.InputValueProvider, Loc: v.Loc}}
+			v.InputValueProvider = l
+		}
+		for _, e := range l {
+			p.checkInputValue(e, item, nm)
+		}
+		return
+	}
+	vals, ok := v.InputValueProvider.(sdl.ObjectVals)
+	if !ok {
+		p.addErr(fmt.Sprintf(`Expected value of type "%s", found %s %s`, t.String(), v.String(), v.AtPosition()))
+		return
+	}
+	given := make(map[sdl.NameValue_]bool)
+	for _, a := range vals {
+		if given[a.Name] {
+			p.addErr(fmt.Sprintf(`There can be only one input field named "%s" %s`, a.Name, a.AtPosition()))
+			continue
+		}
+		given[a.Name] = true
+		var def *sdl.InputValueDef
+		for _, d := range in.InputValueDefs {
+			if a.Name_.Equals(d.Name_) {
+				def = d
+				break
+			}
+		}
+		if def == nil {
+			p.addErr(fmt.Sprintf(`Field "%s" is not defined by type "%s" %s`, a.Name, in.Name, a.AtPosition()))
+			continue
+		}
+		p.checkInputValue(a.Value, def.Type, a.Name_)
+	}
+	//
+	// input fields not given take their default value, otherwise they must be nullable
+	//
+	for _, d := range in.InputValueDefs {
+		if given[d.Name] {
+			continue
+		}
+		if d.DefaultVal != nil {
+			// full slice expression - never append to a slice shared with a variable's value
+			vals = append(vals[:len(vals):len(vals)], &sdl.ArgumentT{Name_: d.Name_, Value: p.defaultValue(d)})
+			continue
+		}
+		if d.Type.Constraint>>d.Type.Depth&1 == 1 {
+			p.addErr(fmt.Sprintf(`Field "%s.%s" of required type "%s" was not provided %s`, in.Name, d.Name, d.Type.String(), v.AtPosition()))
+		}
+	}
+	v.InputValueProvider = vals
+}
+
+// defaultValue returns the default value of the argument or input field d coerced to its type. The value is
+// a copy, as coercion modifies the value it checks and the default belongs to the schema.
+func (p *Parser) defaultValue(d *sdl.InputValueDef) *sdl.InputValue_ {
+	v := copyInputValue(d.DefaultVal)
+	p.checkInputValue(v, d.Type, d.Name_)
+	return v
+}
+
+// copyInputValue returns a copy of v and of the lists and input objects it holds.
+func copyInputValue(v *sdl.InputValue_) *sdl.InputValue_ {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	switch x := v.InputValueProvider.(type) {
+	case sdl.List_:
+		l := make(sdl.List_, len(x))
+		for i, e := range x {
+			l[i] = copyInputValue(e)
+		}
+		c.InputValueProvider = l
+	case sdl.ObjectVals:
+		o := make(sdl.ObjectVals, len(x))
+		for i, a := range x {
+			o[i] = &sdl.ArgumentT{Name_: a.Name_, Value: copyInputValue(a.Value)}
+		}
+		c.InputValueProvider = o
+	}
+	return &c
+}
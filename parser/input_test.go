@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"testing"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const inputSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		search(filter: HeroFilter): [Character]
+		searchAll(filters: [HeroFilter!]): [Character]
+		searchDefault(filter: HeroFilter = {name: "Luke"} ranks: [Int] = 2): [Character]
+	}
+	input HeroFilter {
+		name: String!
+		rank: Int = 3
+		episodes: [Episode]
+		range: Range
+	}
+	input Range {
+		from: Int!
+		to: Int
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+	}`
+
+func TestInputObjectArguments(t *testing.T) {
+
+	s, errs := NewSchema(inputSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `query { search(filter: {name: "Luke" episodes: [JEDI] range: {from: 1 to: 5}}) { id } }`,
+		},
+		{
+			input: `query { searchAll(filters: [{name: "Luke"} {name: "Leia" rank: 1}]) { id } }`,
+		},
+		{ // a single value is coerced to a list
+			input: `query { searchAll(filters: {name: "Luke"}) { id } }`,
+		},
+		{
+			input: `query { search(filter: {name: "Luke" age: 3}) { id } }`,
+			errs:  []string{`Field "age" is not defined by type "HeroFilter" at line: 1 column: 38`},
+		},
+		{
+			input: `query { search(filter: {name: "Luke" name: "Leia"}) { id } }`,
+			errs:  []string{`There can be only one input field named "name" at line: 1 column: 38`},
+		},
+		{
+			input: `query { search(filter: {rank: 1}) { id } }`,
+			errs:  []string{`Field "HeroFilter.name" of required type "String!" was not provided at line: 1 column: 24`},
+		},
+		{
+			input: `query { search(filter: {name: "Luke" range: {to: 5 step: 1}}) { id } }`,
+			errs: []string{
+				`Field "step" is not defined by type "Range" at line: 1 column: 52`,
+				`Field "Range.from" of required type "Int!" was not provided at line: 1 column: 45`,
+			},
+		},
+		{
+			input: `query { searchAll(filters: [{name: "Luke"} {rank: 2}]) { id } }`,
+			errs:  []string{`Field "HeroFilter.name" of required type "String!" was not provided at line: 1 column: 44`},
+		},
+		{
+			input: `query { search(filter: 10) { id } }`,
+			errs:  []string{`Expected value of type "HeroFilter", found 10 at line: 1 column: 24`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
+
+func TestInputObjectDefaults(t *testing.T) {
+
+	s, errs := NewSchema(inputSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	p := NewWithSchema(lexer.New(`query { search(filter: {name: "Luke" range: {from: 1}}) { id } }`), s)
+	d, errs := p.ParseDocument()
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	hero := d.Statements[0].AST.(*ast.OperationStmt).SelectionSet[0].(*ast.Field)
+	filter, ok := hero.Arguments[0].Value.InputValueProvider.(sdl.ObjectVals)
+	if !ok {
+		t.Fatalf("Expected an input object value for filter, got %T", hero.Arguments[0].Value.InputValueProvider)
+	}
+	var rank string
+	for _, a := range filter {
+		if a.Name_.EqualString("rank") {
+			rank = a.Value.String()
+		}
+	}
+	if rank != "3" {
+		t.Errorf(`Expected default rank of 3, got %q`, rank)
+	}
+	if len(filter) != 3 {
+		t.Errorf(`Expected name, range and rank input fields, got %s`, filter)
+	}
+}
+
+func TestArgumentDefaults(t *testing.T) {
+
+	s, errs := NewSchema(inputSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	// the defaults of the schema are not modified by coercion, so each query coerces them again
+	for i := 0; i < 2; i++ {
+		p := NewWithSchema(lexer.New(`query { searchDefault { id } }`), s)
+		d, errs := p.ParseDocument()
+		for _, e := range errs {
+			t.Fatal(e)
+		}
+		hero := d.Statements[0].AST.(*ast.OperationStmt).SelectionSet[0].(*ast.Field)
+		var filter, ranks string
+		for _, a := range hero.Arguments {
+			switch {
+			case a.Name_.EqualString("filter"):
+				filter = a.Value.String()
+			case a.Name_.EqualString("ranks"):
+				ranks = a.Value.String()
+			}
+		}
+		if compare(filter, `{name:"Luke" rank:3}`) {
+			t.Errorf(`Expected the default filter with the default rank, got %s`, filter)
+		}
+		if compare(ranks, `[2]`) {
+			t.Errorf(`Expected the default ranks coerced to a list, got %s`, ranks)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/ast"
+)
+
+// fieldDef is a field of a selection set, gathered from the set itself and the fragments within it,
+// with the type that defines it.
+type fieldDef struct {
+	parent sdl.GQLTypeProvider
+	field  *ast.Field
+}
+
+// responseName is the key of the field in the response - its alias, when it has one, otherwise its name.
+func responseName(f *ast.Field) string {
+	if f.Alias.Exists() {
+		return f.Alias.String()
+	}
+	return f.Name.String()
+}
+
+// collectFieldDefs gathers the fields of set, including those of inline fragments and fragment spreads, by response name.
+// names records the response names in the order first selected.
+func collectFieldDefs(parent sdl.GQLTypeProvider, set []ast.SelectionSetProvider, fields map[string][]fieldDef, names *[]string, visited map[sdl.NameValue_]bool) {
+	for _, s := range set {
+		switch x := s.(type) {
+		case *ast.Field:
+			nm := responseName(x)
+			if _, ok := fields[nm]; !ok {
+				*names = append(*names, nm)
+			}
+			fields[nm] = append(fields[nm], fieldDef{parent: parent, field: x})
+		case *ast.InlineFragment:
+			typ := parent
+			if x.TypeCondAST != nil {
+				typ = x.TypeCondAST
+			}
+			collectFieldDefs(typ, x.SelectionSet, fields, names, visited)
+		case *ast.FragmentSpread:
+			if x.FragStmt == nil || visited[x.Name] {
+				continue
+			}
+			visited[x.Name] = true
+			collectFieldDefs(x.FragStmt.TypeCondAST, x.FragStmt.SelectionSet, fields, names, visited)
+		}
+	}
+}
+
+// checkFieldMerging implements the OverlappingFieldsCanBeMerged rule for set, a selection set of type parent,
+// and the selection sets nested within it. Fields of the same response name, selected directly or through
+// fragments, must be the same field with the same arguments and return types of the same shape, unless their
+// parent types are different object types. Each conflict is reported once.
+func (p *Parser) checkFieldMerging(parent sdl.GQLTypeProvider, set []ast.SelectionSetProvider, reported map[string]bool) {
+
+	fields := make(map[string][]fieldDef)
+	var names []string
+	collectFieldDefs(parent, set, fields, &names, make(map[sdl.NameValue_]bool))
+
+	for _, nm := range names {
+		defs := fields[nm]
+		for i := 0; i < len(defs); i++ {
+			for j := i + 1; j < len(defs); j++ {
+				if reason := p.fieldConflict(defs[i], defs[j], false); len(reason) > 0 {
+					msg := fmt.Sprintf(`Fields "%s" conflict because %s. Use different aliases on the fields to fetch both if this was intentional %s`, nm, reason, defs[j].field.Name.AtPosition())
+					if !reported[msg] {
+						reported[msg] = true
+						p.addErr(msg)
+					}
+				}
+			}
+		}
+	}
+	// nested selection sets
+	for _, nm := range names {
+		for _, d := range fields[nm] {
+			if len(d.field.SelectionSet) > 0 && d.field.SDLfld != nil && d.field.SDLfld.Type.AST != nil {
+				p.checkFieldMerging(d.field.SDLfld.Type.AST, d.field.SelectionSet, reported)
+			}
+		}
+	}
+}
+
+// fieldConflict returns the reason fields a and b, of the same response name, cannot be merged, or "" if they can.
+// exclusive is true when the parents of the enclosing fields are different object types.
+func (p *Parser) fieldConflict(a, b fieldDef, exclusive bool) string {
+
+	var (
+		_, aObj = a.parent.(*sdl.Object_)
+		_, bObj = b.parent.(*sdl.Object_)
+	)
+	exclusive = exclusive || (aObj && bObj && a.parent.TypeName() != b.parent.TypeName())
+	if !exclusive {
+		if !a.field.Name.Equals(b.field.Name) {
+			return fmt.Sprintf(`"%s" and "%s" are different fields`, a.field.Name, b.field.Name)
+		}
+		if !p.sameArguments(a.field.Arguments, b.field.Arguments) {
+			return "they have differing arguments"
+		}
+	}
+	at, bt := a.field.SDLfld, b.field.SDLfld
+	if at == nil || bt == nil {
+		return ""
+	}
+	if typesConflict(at.Type, bt.Type) {
+		return fmt.Sprintf(`they return conflicting types "%s" and "%s"`, at.Type.String(), bt.Type.String())
+	}
+	if len(a.field.SelectionSet) == 0 || len(b.field.SelectionSet) == 0 {
+		return ""
+	}
+	//
+	// compare the fields of the two sub-selections
+	//
+	var (
+		aFields, bFields = make(map[string][]fieldDef), make(map[string][]fieldDef)
+		aNames, bNames   []string
+		reasons          []string
+	)
+	collectFieldDefs(at.Type.AST, a.field.SelectionSet, aFields, &aNames, make(map[sdl.NameValue_]bool))
+	collectFieldDefs(bt.Type.AST, b.field.SelectionSet, bFields, &bNames, make(map[sdl.NameValue_]bool))
+	for _, nm := range aNames {
+		for _, x := range aFields[nm] {
+			for _, y := range bFields[nm] {
+				if reason := p.fieldConflict(x, y, exclusive); len(reason) > 0 {
+					reasons = append(reasons, fmt.Sprintf(`subfields "%s" conflict because %s`, nm, reason))
+				}
+			}
+		}
+	}
+	return strings.Join(reasons, " and ")
+}
+
+// sameArguments reports whether two fields are passed the same arguments. Variables are compared by name.
+func (p *Parser) sameArguments(a, b []*sdl.ArgumentT) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		var found bool
+		for _, y := range b {
+			if x.Name_.Equals(y.Name_) {
+				found = p.argumentString(x.Value) == p.argumentString(y.Value)
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Parser) argumentString(v *sdl.InputValue_) string {
+	if u, ok := p.varUses[v]; ok {
+		return "$" + u.name.String()
+	}
+	if v == nil || v.InputValueProvider == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// typesConflict reports whether two field types have a different list or non-null shape, or are different leaf types.
+func typesConflict(a, b *sdl.GQLtype) bool {
+	if a.Depth != b.Depth || a.Constraint != b.Constraint {
+		return true
+	}
+	if isLeaf(a) || isLeaf(b) {
+		return a.Name != b.Name
+	}
+	return false
+}
+
+func isLeaf(t *sdl.GQLtype) bool {
+	if t.IsScalar() {
+		return true
+	}
+	_, ok := t.AST.(*sdl.Enum_)
+	return ok
+}
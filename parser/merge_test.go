@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const mergeSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		hero(episode: Episode = JEDI): [Character]
+		search: [SearchResult]
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+		friends: [Character]
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+		friends: [Character]
+		totalCredits: Int
+	}
+	type Droid implements Character {
+		id: String!
+		name: String!
+		friends: [Character]
+		primaryFunction: String
+	}
+	union SearchResult = Human | Droid`
+
+func TestFieldMerging(t *testing.T) {
+
+	s, errs := NewSchema(mergeSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{ // the same field directly and through a fragment
+			input: `query { hero { name ...names ... on Human { name } } }
+			        fragment names on Character { name }`,
+		},
+		{
+			input: `query { hero { name: id name } }`,
+			errs:  []string{`Fields "name" conflict because "id" and "name" are different fields. Use different aliases on the fields to fetch both if this was intentional at line: 1 column: 25`},
+		},
+		{
+			input: `query { hero(episode: JEDI) { id } hero(episode: EMPIRE) { name } }`,
+			errs:  []string{`Fields "hero" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional at line: 1 column: 36`},
+		},
+		{ // different object types never apply to the same object
+			input: `query { search { ... on Human { key: id } ... on Droid { key: name } } }`,
+		},
+		{
+			input: `query { search { ... on Human { key: totalCredits } ... on Droid { key: primaryFunction } } }`,
+			errs:  []string{`Fields "key" conflict because they return conflicting types "Int" and "String". Use different aliases on the fields to fetch both if this was intentional at line: 1 column: 73`},
+		},
+		{
+			input: `query { hero { friends { name } ...friendIds } }
+			        fragment friendIds on Character { friends { name: id } }`,
+			errs: []string{`Fields "friends" conflict because subfields "name" conflict because "name" and "id" are different fields. Use different aliases on the fields to fetch both if this was intentional at line: 2 column: 46`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/resolver"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	s, errs := NewSchema(concurrentSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	var (
+		mx    sync.Mutex
+		calls []string
+	)
+	record := func(next resolver.FieldResolveFn) resolver.FieldResolveFn {
+		return func(ctx context.Context, f *resolver.Field) (sdl.InputValueProvider, error) {
+			mx.Lock()
+			calls = append(calls, fmt.Sprintf("%s %s.%s resolver=%v parent=%v", f.Path, f.ParentType, f.Name, f.Resolver != nil, f.Parent != nil))
+			mx.Unlock()
+			return next(ctx, f)
+		}
+	}
+	// redact replaces the value of name fields and must run inside record
+	redact := func(next resolver.FieldResolveFn) resolver.FieldResolveFn {
+		return func(ctx context.Context, f *resolver.Field) (sdl.InputValueProvider, error) {
+			v, err := next(ctx, f)
+			if f.Name == "name" && v != nil {
+				return sdl.String_("redacted"), err
+			}
+			return v, err
+		}
+	}
+	p := NewWithSchema(lexer.New(`query { hero(episode: JEDI) { id name } }`), s)
+	p.Use(record, redact)
+	p.Resolver.Register("Query/hero", resolveHeroes)
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	result, errs := p.ExecuteDocument()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if strings.Contains(result, "Luke") || strings.Count(result, "redacted") != 2 || !strings.Contains(result, "JEDI-1") {
+		t.Errorf("Unexpected result: %s", result)
+	}
+	for _, v := range []string{
+		"Query/hero Query.hero resolver=true parent=false",
+		"Query/hero/id Character.id resolver=false parent=true",
+		"Query/hero/name Character.name resolver=false parent=true",
+	} {
+		var found bool
+		for _, c := range calls {
+			found = found || c == v
+		}
+		if !found {
+			t.Errorf("Expected middleware call %q in %q", v, calls)
+		}
+	}
+}
+
+func TestMiddlewareError(t *testing.T) {
+
+	s, errs := NewSchema(concurrentSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	deny := func(next resolver.FieldResolveFn) resolver.FieldResolveFn {
+		return func(ctx context.Context, f *resolver.Field) (sdl.InputValueProvider, error) {
+			if f.Resolver != nil {
+				return nil, fmt.Errorf(`Access to "%s" denied`, f.Name)
+			}
+			return next(ctx, f)
+		}
+	}
+	p := NewWithSchema(lexer.New(`query { hero(episode: JEDI) { id } }`), s)
+	p.Use(deny)
+	p.Resolver.Register("Query/hero", func(ctx context.Context, resp sdl.InputValueProvider, args sdl.ObjectVals) <-chan string {
+		t.Error("Resolver executed despite middleware error")
+		return resolveHeroes(ctx, resp, args)
+	})
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	_, errs = p.ExecuteDocument()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `Access to "hero" denied`) {
+		t.Errorf("Expected access denied error, got %v", errs)
+	}
+}
@@ -81,7 +81,7 @@ func TestValidateDirectives(t *testing.T) {
 	// Test
 	//
 	var input = `
-	query XYZ ($expandedInfo: Boolean = true)  @ include(arg1: 2 arg2: 3.2) {
+	query XYZ @ include(arg1: 2 arg2: 3.2) {
 	     allPersons(last: 2 first : [["abc", "def" ]["def"]]) {
 	         name @  include(arg1:3 arg2: 4.5 )
 	         age (ScaleBy: 1.5) 
@@ -99,7 +99,7 @@ func TestValidateDirectives(t *testing.T) {
 
 	var expectedErr []string
 	var expectedDoc string = `         
-	   query XYZ ( $expandedInfo : Boolean = true) @include (arg1:2 arg2:3.2){ 
+	   query XYZ @include (arg1:2 arg2:3.2){ 
                         allPersons(last:2 first:[["abc" "def"]  ["def"] ] ) {
                                 name@include(arg1:3 arg2:4.5) 
                                 age(ScaleBy:1.5)
@@ -127,6 +127,7 @@ func TestValidateDirectives(t *testing.T) {
 			t.Errorf(`Unexpected: JSON output wrong. `)
 		}
 	}
+	checkUnusedVariable(input, "query XYZ", " ($expandedInfo: Boolean = true) ", `Variable "$expandedInfo" is not used by operation "XYZ" at line: 2 column: 14`, t)
 
 }
 
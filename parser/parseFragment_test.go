@@ -516,7 +516,6 @@ func TestFragmentAttributeRepeated(t *testing.T) {
 
 	expectedErr := []string{
 		//	`Field "XXX" is not a member of "Character" (SDL Interface "Character") at line: 17 column: 4`,
-		`Field "Human.Query/hero(middleComparision)/appearsIn" has already been specified at line: 8 column: 6`,
 	}
 
 	l := lexer.New(input)
@@ -597,7 +596,6 @@ func TestFragmentInlineWithUnionEnclosingType(t *testing.T) {
 
 	var parseErrs []string = []string{
 		`Expected a type on-condition as enclosing type, "USearchResult", is a Union, at line: 13 column: 4`,
-		`Field "Droid.Query/hero/name" has already been specified at line: 10 column: 5`,
 	}
 
 	l := lexer.New(input)
@@ -767,7 +765,7 @@ func TestMultiStmtConcurrent1(t *testing.T) {
 	  leftComparison: hero(episode: NEWHOPE) {
 	    ...comparisonFields
 	    totalCredits
-	    starships
+	    starships { name }
 	  }
 	  middleComparision: hero(episode: JEDI ) {
 	    ...comparisonFields
@@ -818,8 +816,10 @@ fragment comparisonFields on Character {
                  totalCredits : 5532
          starships : [ 
                  {
+                 name : "Falcon"
                  }
                  {
+                 name : "Cruiser"
                  } ] 
          }
          {
@@ -837,6 +837,7 @@ fragment comparisonFields on Character {
          totalCredits : 2532
          starships : [ 
                  {
+                 name : "BattleStar"
                  } ] 
          }  ]
          middleComparision : [ 
@@ -1792,9 +1793,7 @@ fragment comparisonFields on Character {
 }
 `
 
-	var parseErrs []string = []string{
-		`Field "Human.Query/hero(middleComparision)/appearsIn" has already been specified at line: 7 column: 6`,
-	}
+	var parseErrs []string
 	var execErrs []string
 	var expectedResult string
 
@@ -1915,7 +1914,7 @@ func TestFragmentTypeCond1x(t *testing.T) {
 		setup(inputSDL, t)
 	}
 
-	var input = `query ($expandedInfo: Boolean = true) {
+	var input = `query {
 	leftComparison: hero(episode: EMPIRE) {
 	   ...comparisonHuman
 	}
@@ -2022,6 +2021,7 @@ fragment comparisonCharacter on Character {
 			t.Log(result)
 		}
 	}
+	checkUnusedVariable(input, "query", " ($expandedInfo: Boolean = true)", `Variable "$expandedInfo" is not used at line: 1 column: 9`, t)
 }
 
 func TestFragmentTypeCond1withErrs(t *testing.T) {
@@ -2092,8 +2092,6 @@ fragment comparisonCharacter on Character {
 
 	var parseErrs []string = []string{
 		`"DRTYPE" is not a member of Enum type Episode at line: 2 column: 32`,
-		`Field "Human.Query/hero(leftComparison)/appearsIn" has already been specified at line: 6 column: 3`,
-		`Field "Human.Query/hero(leftComparison)/name" has already been specified at line: 21 column: 3`,
 	}
 	var execErrs []string
 	var expectedResult string
@@ -2139,7 +2137,7 @@ func TestFragmentTypeCond2(t *testing.T) {
 		`
 		setup(inputSDL, t)
 	}
-	var input = `query ($expandedInfo: Boolean = true) {
+	var input = `query {
 	HumanComparison: hero(episode: JEDI) {
 	   ...comparisonHuman
 	}
@@ -2246,6 +2244,7 @@ fragment comparisonCharacter on Character {
 			t.Log(result)
 		}
 	}
+	checkUnusedVariable(input, "query", " ($expandedInfo: Boolean = true)", `Variable "$expandedInfo" is not used at line: 1 column: 9`, t)
 }
 
 func TestInlineFragmentTypeCondInterface(t *testing.T) {
@@ -2538,9 +2537,7 @@ fragment comparisonFields on Character {				# fragment stmt no directives
 }
 `
 
-	var expectedErr []string = []string{
-		`Field "Character.Query/hero(rightComparison)/name" has already been specified at line: 12 column: 14`,
-	}
+	var expectedErr []string
 
 	l := lexer.New(input)
 	p := New(l)
@@ -3606,9 +3603,7 @@ func TestRootInterfaceWithFragmentIsImplementedDupFields(t *testing.T) {
 		
 		`
 
-	var parsedErrs []string = []string{
-		`Field "Business.Query/item/entity/name" has already been specified at line: 13 column: 4`,
-	}
+	var parsedErrs []string
 
 	l := lexer.New(input)
 	p := New(l)
@@ -3686,9 +3681,7 @@ func TestRootInterfaceWithOnlineFragmentDupFields(t *testing.T) {
 		
 		`
 
-	var parsedErrs []string = []string{
-		`Field "Business.Query/item/entity/name" has already been specified at line: 7 column: 10`,
-	}
+	var parsedErrs []string
 
 	l := lexer.New(input)
 	p := New(l)
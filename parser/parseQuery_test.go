@@ -889,14 +889,14 @@ func TestQueryCoerceInt2ListDepth2_46(t *testing.T) {
 	}
 
 	var input string = `
-	query XYZ ($expandedInfo: Boolean = false) {
+	query XYZ {
 	     allPersons(last: 2) {
 	         name 
 	     }
 	}
 `
 	var expectedDoc string = `
-		query XYZ ($expandedInfo: Boolean = false) {
+		query XYZ {
 		     allPersons(last: 2 first :3) {
 		         name
 		     }
@@ -921,6 +921,7 @@ func TestQueryCoerceInt2ListDepth2_46(t *testing.T) {
 			t.Errorf(`Unexpected document for %s. `, t.Name())
 		}
 	}
+	checkUnusedVariable(input, "query XYZ", " ($expandedInfo: Boolean = false)", `Variable "$expandedInfo" is not used by operation "XYZ" at line: 2 column: 14`, t)
 
 }
 
@@ -936,14 +937,14 @@ func TestQueryCoerceInt2ListDepth3_47(t *testing.T) {
 	// Test
 	//
 	var input string = `
-	query XYZ ($expandedInfo: Boolean = false) {
+	query XYZ {
 	     allPersons(last: 3) {
 	         name 
 	     }
 	}
 `
 	var expectedDoc string = `
-		query XYZ ($expandedInfo: Boolean = false) {
+		query XYZ {
 		     allPersons(last: 3 first: 3) {
 		         name
 		     }
@@ -965,6 +966,7 @@ func TestQueryCoerceInt2ListDepth3_47(t *testing.T) {
 			t.Errorf(`Unexpected document for %s. `, t.Name())
 		}
 	}
+	checkUnusedVariable(input, "query XYZ", " ($expandedInfo: Boolean = false)", `Variable "$expandedInfo" is not used by operation "XYZ" at line: 2 column: 14`, t)
 	//
 	// Teardown
 	//
@@ -1238,9 +1240,7 @@ func TestQueryInlineFragmentWithSameAttributeTrue(t *testing.T) {
 		}
 	`
 
-	var expectedErr []string = []string{
-		`Field "Person.Query/allPersons/age" has already been specified at line: 14 column: 12`,
-	}
+	var expectedErr []string
 
 	expectedResult := ``
 
@@ -1293,7 +1293,6 @@ func TestQueryInlineFragmentWithSameAttributeFALSE(t *testing.T) {
 	`
 
 	var expectedErr []string = []string{
-		`Field "Person.Query/allPersons/age" has already been specified at line: 14 column: 12`,
 		`Argument "resp" must be defined (type "Int!") at line: 6 column: 13`,
 	}
 
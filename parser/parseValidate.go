@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/logger"
 )
 
 func (p *Parser) validateArguments(qArguments *[]*sdl.ArgumentT, argDefs sdl.InputValueDefs, item sdl.Name_, root sdl.GQLTypeProvider) {
@@ -14,8 +15,9 @@ func (p *Parser) validateArguments(qArguments *[]*sdl.ArgumentT, argDefs sdl.Inp
 		for _, argDef := range argDefs {
 			if argVal.Name_.Equals(argDef.Name_) {
 				found = true
+				p.assignVarTypes(argVal.Value, argDef.Type, argDef.DefaultVal != nil)
 				// validate argument value against type expected by schema definition
-				argVal.Value.CheckInputValueType(argDef.Type, argVal.Name_, &p.perror)
+				p.checkInputValue(argVal.Value, argDef.Type, argVal.Name_)
 				break
 			}
 		}
@@ -37,11 +39,9 @@ func (p *Parser) validateArguments(qArguments *[]*sdl.ArgumentT, argDefs sdl.Inp
 		if !found {
 			if argDef.DefaultVal != nil {
 				// create argument with system defaults
-				iv := &sdl.ArgumentT{Name_: argDef.Name_, Value: argDef.DefaultVal}
+				iv := &sdl.ArgumentT{Name_: argDef.Name_, Value: p.defaultValue(argDef)}
 				*qArguments = append(*qArguments, iv)
-				for _, v := range *qArguments {
-					fmt.Printf("* Argument: %#v\n", *v)
-				}
+				p.log.Log(logger.Debug, "argument default applied", "argument", argDef.Name_, "value", argDef.DefaultVal, "item", item)
 
 			} else {
 				p.addErr(fmt.Sprintf(`Argument %q must be defined (type %q) %s`, argDef.Name_, argDef.Type.String(), item.AtPosition()))
@@ -60,7 +60,7 @@ func (p *Parser) validateDirectives(qDirectives []*sdl.DirectiveT, root sdl.GQLT
 	for _, qDir := range qDirectives {
 		// get sdl.Directive_ AST from cache.
 		// note:resolveDependents() will have caught non-existent directives, so no need to check for not-exist errors
-		sdDirAST, _ := p.tyCache.FetchAST(qDir.Name_.Name)
+		sdDirAST, _ := p.tyCache.FetchAST(qDir.Name_.Name, p.log)
 		if sdDirAST == nil {
 			p.abort = true
 			return
@@ -88,8 +88,8 @@ func (p *Parser) confirmASTassigned(sdlFld *sdl.Field_) {
 	//
 	if !sdlFld.Type.IsScalar() && sdlFld.Type.AST == nil {
 		var err error
-		p.logr.Println("Assign GQLtype.AST for sdlFld.Type.Name from cache")
-		sdlFld.Type.AST, err = p.tyCache.FetchAST(sdlFld.Type.Name)
+		p.log.Log(logger.Debug, "assign type AST from cache", "field", sdlFld.Name_, "type", sdlFld.Type.Name)
+		sdlFld.Type.AST, err = p.tyCache.FetchAST(sdlFld.Type.Name, p.log)
 		if err != nil {
 			p.addErr(err.Error())
 		}
@@ -4,21 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	sdl "github.com/rosshpayne/graph-sdl/ast"
-	db "github.com/rosshpayne/graph-sdl/document"
 	lex "github.com/rosshpayne/graph-sdl/lexer"
 	pse "github.com/rosshpayne/graph-sdl/parser"
 	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/auth"
 	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/logger"
 	"github.com/rosshpayne/graphql/resolver"
+	"github.com/rosshpayne/graphql/store"
 	"github.com/rosshpayne/graphql/token"
+	"github.com/rosshpayne/graphql/tracing"
 )
 
 const (
@@ -32,8 +34,6 @@ const (
 	QUERY        = `query`
 	MUTATION     = `mutation`
 	SUBSCRIPTION = `subscription`
-	//
-	logrFlags = log.LstdFlags | log.Lshortfile
 )
 
 type Argument struct {
@@ -57,26 +57,47 @@ type (
 
 		curToken  *token.Token
 		peekToken *token.Token
+		depth     int // braces open at curToken, used to resume parsing at the next statement after an error
+		lbraces   int // count of { read
 
-		logr *log.Logger
-		logf *os.File
+		log    logger.Logger
+		tracer tracing.Tracer
 
-		tyCache   *pse.Cache_
+		store     store.SchemaStore
+		schema    *Schema // when assigned, tyCache is the schema's types when the parser was created
+		tyCache   *typeCache
 		stmtCache *Cache_
 		//stmtCache *pse.Cache_
 
-		responseMap map[string]*sdl.InputValueProvider //struct{}
-		respOrder   []string                           // slice of field paths in order executed.	// TODO remove - don't use it
+		respOrder []string // slice of field paths in order executed.	// TODO remove - don't use it
 		//response  []*ast.ResponseValue // conerts response from reolver  to internal sdl.ObjectVal
 
 		root    ast.GQLStmtProvider
 		rootVar []*ast.VariableDef
 
-		Resolver *resolver.Resolvers
+		// per document state, populated by ParseDocument and consumed by ExecuteDocument
+		doc            *ast.Document
+		fragmentStmts  map[sdl.NameValue_]*ast.FragmentStmt
+		operationStmts map[sdl.NameValue_]*ast.OperationStmt
+		varUses        map[*sdl.InputValue_]*varUse      // variable references by the argument value that stands in for them
+		varUsesByStmt  map[ast.GQLStmtProvider][]*varUse // variable references of each statement
+		stmtVarUses    []*varUse                         // variable references of the statement being parsed
+		inFragment     bool                              // parsing a fragment statement, whose variables are defined by the operations that spread it
+		syntax         bool                              // parsing with Parse, which leaves variables unsubstituted
+		variables      map[string]interface{}            // variable values by name, assigned by SetVariables
+
+		Resolver        *resolver.Resolvers
+		resolverTimeout time.Duration
+		middleware      []resolver.Middleware
+
+		ctx             context.Context // carries request values, such as the principal, to validation, middleware and resolvers
+		rejectForbidden bool
+		sharedFragments bool    // fragments may be spread by other documents, so are not reported when unused
+		fieldErrs       []error // errors of fields that are null in the response. Guarded by perrorMx.
 
 		parseFns map[token.TokenType]parseFn
 		perror   []error
-		perrorMx sync.Mutex
+		perrorMx sync.Mutex // guards perror and abort while statement fields are executed concurrently
 		sync.Mutex
 	}
 )
@@ -85,24 +106,35 @@ type (
 var TypeResolveErr = errors.New("")
 
 var (
-	//	enumRepo      ast.EnumRepo_
-	// TODO why are these pkg variables - rethink?
-	FragmentStmts  map[sdl.NameValue_]*ast.FragmentStmt
-	OperationStmts map[sdl.NameValue_]*ast.OperationStmt
-	noName         string = "__NONAME__"
+	noName string = "__NONAME__"
+	// sdlMx serialises access to the type table graph-sdl keeps at package level (sdl.TyCache).
+	// It is reloaded from the type cache and read by the sdl validation methods, so
+	// the validation phase of concurrent documents must not overlap.
+	sdlMx sync.Mutex
+	// dynamoStore is the schema store of parsers created with New.
+	dynamoStore = store.NewDynamoDB(store.DefaultRegion, store.DefaultTable)
 )
 
+// New returns a parser whose SDL types are sourced from the DynamoDB table graph-sdl persists to.
 func New(l *lexer.Lexer) *Parser {
+	return NewWithStore(l, dynamoStore)
+}
+
+// NewWithStore returns a parser whose SDL types are sourced from s.
+func NewWithStore(l *lexer.Lexer, s store.SchemaStore) *Parser {
 	p := &Parser{
-		l: l,
+		l:      l,
+		store:  s,
+		log:    logger.Nop(),
+		tracer: tracing.Nop(),
+		ctx:    context.Background(),
 	}
 
-	// GL type cache
-	p.tyCache = pse.NewCache()
 	// GL statement cache
-	p.stmtCache = newCache()
+	p.stmtCache = newCache(p.log)
 	// cache for resolver functions
 	p.Resolver = resolver.New()
+	p.resolverTimeout = ResolverTimeoutMS * time.Millisecond
 
 	p.parseFns = make(map[token.TokenType]parseFn)
 	// regiser Parser methods for each statement type
@@ -117,12 +149,6 @@ func New(l *lexer.Lexer) *Parser {
 	// remove cacheClar before releasing..
 	//
 	//ast.CacheClear()
-	//
-	//  open log file and set logger
-	//
-	p.logf = openLogFile()
-	p.logr = log.New(p.logf, "GQL:", logrFlags)
-	p.tyCache.SetLogger(p.logr)
 
 	return p
 }
@@ -134,11 +160,19 @@ func New(l *lexer.Lexer) *Parser {
 // 	OperationStmts = make(map[sdl.NameValue_]*ast.OperationStmt)
 // }
 
+// ClearCache discards the cached SDL types of the parser's store and document, so they are sourced
+// afresh for the next document parsed. Documents in progress keep the types they hold.
+// The types of a Schema are replaced only by its Reload.
 func (p *Parser) ClearCache() {
-	p.logr.Print("Clear type cache")
-	if p.tyCache != nil {
-		p.tyCache.CacheClear()
+	if p.schema != nil {
+		return
+	}
+	p.log.Log(logger.Info, "clear type cache", "document", p.document)
+	doc := p.document
+	if len(doc) == 0 {
+		doc = defaultDoc
 	}
+	dropTypeCache(p.store, doc)
 }
 
 func (p *Parser) Loc() *sdl.Loc_ {
@@ -151,17 +185,15 @@ func (p *Parser) Loc() *sdl.Loc_ {
 // 	ast.CacheClear()
 // }
 func (p *Parser) printToken(s ...string) {
+	var msg string
 	if len(s) > 0 {
-		fmt.Printf("** Current Token: [%s] %s %s %s %v %s %s [%s]\n", s[0], p.curToken.Type, p.curToken.Literal, p.curToken.Cat, p.curToken.IsScalarType, "Next Token:  ", p.peekToken.Type, p.peekToken.Literal)
-	} else {
-		fmt.Println("** Current Token: ", p.curToken.Type, p.curToken.Literal, p.curToken.Cat, "Next Token:  ", p.peekToken.Type, p.peekToken.Literal)
+		msg = s[0]
 	}
+	p.log.Log(logger.Debug, "token", "at", msg, "type", p.curToken.Type, "literal", p.curToken.Literal, "cat", p.curToken.Cat, "nextType", p.peekToken.Type, "nextLiteral", p.peekToken.Literal)
 }
 
-var eof bool
-
 func (p *Parser) hasError() bool {
-	if len(p.perror) > 17 || p.abort || eof {
+	if len(p.perror) > 17 || p.abort {
 		return true
 	}
 	return false
@@ -196,12 +228,27 @@ func (p *Parser) nextToken(s ...string) {
 	p.curToken = p.peekToken
 
 	p.peekToken = p.l.NextToken() // get another token from lexer:    [,+,(,99,Identifier,keyword etc.
+	if p.curToken != nil {
+		switch p.curToken.Type {
+		case token.LBRACE:
+			p.depth++
+			p.lbraces++
+		case token.RBRACE:
+			if p.depth > 0 {
+				p.depth--
+			}
+		}
+	}
 	if len(s) > 0 {
 		p.printToken(s[0])
 	}
-	if p.curToken != nil && p.curToken.Illegal {
-		//if p.curToken.Illegal {
-		p.addErr(fmt.Sprintf("Illegal %s token, [%s]", p.curToken.Type, p.curToken.Literal))
+	if p.curToken != nil && (p.curToken.Illegal || p.curToken.Type == token.ILLEGAL) {
+		// the lexer returns a rune or number that breaks the grammar as an ILLEGAL token positioned at the offending character
+		if utf8.RuneCountInString(p.curToken.Literal) == 1 {
+			p.addErr(fmt.Sprintf(`Illegal character "%s"`, p.curToken.Literal))
+		} else {
+			p.addErr(fmt.Sprintf(`Illegal token "%s"`, p.curToken.Literal))
+		}
 	}
 	// if $variable present then mark the identier as a VALUE
 	if p.curToken != nil && p.curToken.Literal == token.DOLLAR {
@@ -210,32 +257,11 @@ func (p *Parser) nextToken(s ...string) {
 	}
 }
 
-func openLogFile() *os.File {
-	logf, err := os.OpenFile("gqlserver.sys.log", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
-	if err != nil {
-		log.Fatal(err)
-	}
-	return logf
-}
-
-func (p *Parser) closeLogFile() {
-	if err := p.logf.Close(); err != nil {
-		log.Fatal(err)
-	}
-}
-
-var api *ast.Document //TODO: create session map to hold SesUId and api. Pass SessUID to client and client sends to server for each request
-
 // ==================== Start =========================
 
-func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
+func (p *Parser) ParseDocument(doc ...string) (_ *ast.Document, errs []error) {
 
-	defer p.closeLogFile()
-
-	FragmentStmts = make(map[sdl.NameValue_]*ast.FragmentStmt)
-	OperationStmts = make(map[sdl.NameValue_]*ast.OperationStmt)
-
-	api = &ast.Document{}
+	api := p.newDocument()
 	//	api.Statements = []ast.Statement{} // contains operational stmts (query, mutation, subscriptions) and fragment stmts
 	//
 	// preparation - get Schema ast from db
@@ -246,7 +272,6 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		MrootAST  sdl.GQLTypeProvider
 		QrootAST  sdl.GQLTypeProvider
 		schema    *sdl.Schema_
-		allErrors []error
 		err       error
 	)
 	//
@@ -255,51 +280,30 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 	if len(p.document) == 0 {
 		p.document = defaultDoc
 	}
-	db.SetDefaultDoc(p.document)
-	if len(doc) == 0 {
-		db.SetDocument(p.document)
-	} else {
+	if len(doc) > 0 {
 		p.document = doc[0]
-		db.SetDocument(doc[0])
 	}
+	if p.schema == nil {
+		p.tyCache = getTypeCache(p.store, p.document)
+	}
+	// the span of the current phase, parse then validate
+	finish := p.tracer.Start(tracing.Span{Kind: tracing.Parse})
+	defer func() {
+		finish(firstErr(errs))
+	}()
 	//
 	// Phase 1a: parse all statements (query, fragment) in the document and add to cache if statement has no errors
 	//          parsing can be done without reference to SDL, however, during the validation phase we will need
 	//          to know the type information provided by the SDL.
 	//
-	var failed bool
-	for p.curToken.Type != token.EOF {
-		//
-		var stmt *ast.Statement
-		stmtAST, stmtType := p.parseStatement()
-		if stmtAST == nil {
-			// TODO: instead of returning move onto next stmt (if there is one), so we can continue parsing.
-			return nil, p.perror
-		}
-		if p.hasError() { // uncommented 15April
-			return nil, p.perror
-		}
-
-		if stmtAST != nil {
-			stmt = &ast.Statement{Type: stmtType, AST: stmtAST, Name: string(stmtAST.StmtName())}
-			api.Statements = append(api.Statements, stmt)
-		} else {
-			stmt = &ast.Statement{Type: stmtType, AST: nil, Name: string(stmtAST.StmtName())}
-			api.Statements = append(api.Statements, stmt)
-			failed = true
-		}
-		fmt.Printf("Statement: %s %#v\ns", string(stmtAST.StmtName()), stmt)
-		if stmtAST != nil {
-			p.stmtCache.addEntry(stmt.AST.StmtName(), stmt.AST) //	ast.Add2StmtCache(stmt.AST.StmtName(), stmt.AST)
-		}
-		allErrors = append(allErrors, p.perror...)
-		p.perror = nil
-	}
+	failed, allErrors := p.parseStatements(api)
 	//
-	fmt.Println("+++ EOF +++")
+	p.log.Log(logger.Debug, "parse complete", "statements", len(api.Statements))
 	if failed {
 		return nil, allErrors
 	}
+	finish(firstErr(allErrors))
+	finish = p.tracer.Start(tracing.Span{Kind: tracing.Validate})
 	//
 	// Phase 1b: Get the entry point for the graph
 	//
@@ -321,7 +325,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 	//
 	// fetch schema within the SDL document
 	//
-	schemaAST, err = p.tyCache.FetchAST(sdl.NameValue_("schema")) // schema is standard name
+	schemaAST, err = p.tyCache.FetchAST(sdl.NameValue_("schema"), p.log) // schema is standard name
 	if err != nil {
 		p.addErr(err.Error())
 	}
@@ -342,7 +346,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		case "query":
 			// get query rootAST
 			if QrootAST == nil {
-				QrootAST, err = p.tyCache.FetchAST(schema.Query.Name)
+				QrootAST, err = p.tyCache.FetchAST(schema.Query.Name, p.log)
 				if err != nil {
 					p.addErr(err.Error())
 				}
@@ -356,7 +360,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		case "mutation":
 			// get mutation rootAST
 			if MrootAST == nil {
-				MrootAST, err = p.tyCache.FetchAST(schema.Mutation.Name)
+				MrootAST, err = p.tyCache.FetchAST(schema.Mutation.Name, p.log)
 				if err != nil {
 					p.addErr(err.Error())
 				}
@@ -370,7 +374,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		case "subscription":
 			// get subscription rootAST
 			if SrootAST == nil {
-				SrootAST, err = p.tyCache.FetchAST(schema.Subscription.Name)
+				SrootAST, err = p.tyCache.FetchAST(schema.Subscription.Name, p.log)
 				if err != nil {
 					p.addErr(err.Error())
 				}
@@ -385,14 +389,28 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 	allErrors = append(allErrors, p.perror...)
 	p.perror = nil
 	//
+	// phase 1c: fragment spreads. Validation follows spreads into fragments so a cycle aborts the document.
+	//
+	p.checkFragmentCycles(api)
+	if len(p.perror) > 0 {
+		return nil, append(allErrors, p.perror...)
+	}
+	if !p.sharedFragments {
+		p.checkUnusedFragments(api)
+	}
+	if len(p.perror) > 0 {
+		failed = true
+		allErrors = append(allErrors, p.perror...)
+		p.perror = nil
+	}
+	//
 	// phase 2  - check statment names - can only be one short named (ie. no name provided) statement
 	//
-	if len(OperationStmts) > 1 { //  OperationStmts  is populated in parseOperation func
+	if len(p.operationStmts) > 1 { //  operationStmts  is populated in parseOperation func
 		var (
 			nm    string
 			short int
 		)
-		fmt.Println("here...3")
 		// look for shortened version of statments - which are populated at parse stmt.
 		// when no stmt name is specified set name to "__NONAME__/<i>"
 		for i := 0; ; i++ {
@@ -401,7 +419,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 			} else {
 				nm = noName + "/" + strconv.Itoa(i)
 			}
-			if _, ok := OperationStmts[sdl.NameValue_(nm)]; ok {
+			if _, ok := p.operationStmts[sdl.NameValue_(nm)]; ok {
 				short++
 			} else {
 				break
@@ -418,6 +436,10 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 	// phase 3a: validate any fragment stmt - resolve ALL types. Once complete all type's AST will reside in the cache
 	//                  			  and  *sdl.GQLtype.AST assigned where applicable
 	//
+	// validation reads sdl.TyCache, which is shared by all parsers.
+	//
+	sdlMx.Lock()
+	defer sdlMx.Unlock()
 	for _, stmt := range api.Statements {
 		if stmt.Type != "fragment" {
 			continue
@@ -428,7 +450,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		}
 		// execute fragment statements first
 		// generic checks
-		fmt.Printf(" resolveSDLdependents for fragment stmt %s\n", stmt.AST.StmtName())
+		p.log.Log(logger.Debug, "validate fragment", "name", stmt.Name)
 		p.resolveSDLdependents(stmt.AST, p.tyCache)
 		if p.hasError() {
 			return nil, p.perror
@@ -458,7 +480,7 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		if stmt.Type == "fragment" {
 			continue
 		}
-		fmt.Println("here..5")
+		p.log.Log(logger.Debug, "validate operation", "name", stmt.Name)
 		//
 		// generic checks
 		//
@@ -468,15 +490,23 @@ func (p *Parser) ParseDocument(doc ...string) (*ast.Document, []error) {
 		if p.hasError() {
 			return nil, p.perror
 		}
+		// bind variables referenced in fragments to this operation's variables before their arguments are validated
+		p.bindVariables(stmt.AST.(*ast.OperationStmt))
 		//
 		// check each field referenced in the stmt against the respective root type.
 		//
 		p.checkFields(stmt.RootAST, stmt.AST)
+		if p.rejectForbidden && !p.hasError() {
+			p.checkForbidden(stmt.AST.(*ast.OperationStmt))
+		}
 		//
 		// type specific checks
 		//
 		stmt.AST.CheckIsInputType(&p.perror)
 		stmt.AST.CheckInputValueType(&p.perror)
+		if len(p.perror) == 0 {
+			p.checkVariables(stmt.AST.(*ast.OperationStmt))
+		}
 		//
 		// add stmt to stmt cache
 		//
@@ -502,20 +532,27 @@ func (p *Parser) ExecuteDocument() (string, []error) {
 	//
 	// Execute phase
 	//
-	fmt.Println("==== Execute ===")
 	var (
 		executed   bool
 		resultJson string
 	)
-	for _, stmt := range api.Statements {
+	if p.doc == nil {
+		p.addErr("Document has not been parsed")
+		return "", p.perror
+	}
+	p.log.Log(logger.Debug, "execute document", "statements", len(p.doc.Statements))
+	p.fieldErrs = nil
+	for _, stmt := range p.doc.Statements {
 		if stmt.Type == "fragment" {
 			continue
 		}
 		if len(p.xStmt) > 0 && stmt.Name != p.xStmt {
 			continue
 		}
-		fmt.Println("==== Execute === Got stmt ..", stmt.AST.String())
+		p.log.Log(logger.Debug, "execute statement", "name", stmt.Name)
+		finish := p.tracer.Start(tracing.Span{Kind: tracing.Operation, Operation: operationName(stmt.Name), OperationType: stmt.Type})
 		result := p.executeStmt(stmt)
+		finish(firstErr(p.perror))
 		executed = true
 		allErrors = append(allErrors, p.perror...)
 		p.perror = nil
@@ -529,9 +566,101 @@ func (p *Parser) ExecuteDocument() (string, []error) {
 	allErrors = append(allErrors, p.perror...)
 
 	if len(allErrors) > 0 {
-		resultJson = ``
+		return ``, allErrors
+	}
+	// field errors do not void the response, which reports them
+	return p.addExtensions(p.addFieldErrors(resultJson)), p.fieldErrs
+}
+
+// addExtensions appends the extensions contributed by the parser's tracer to the response.
+func (p *Parser) addExtensions(resultJson string) string {
+	ext, err := tracing.MarshalExtensions(p.tracer)
+	if err != nil {
+		p.log.Log(logger.Warn, "marshal extensions", "error", err)
+		return resultJson
+	}
+	i := strings.LastIndex(resultJson, "}")
+	if ext == nil || i < 0 {
+		return resultJson
+	}
+	return resultJson[:i] + ",\nextensions: " + string(ext) + "\n" + resultJson[i:]
+}
+
+// newDocument resets the per document state of the parser and returns the document to parse into.
+func (p *Parser) newDocument() *ast.Document {
+	p.fragmentStmts = make(map[sdl.NameValue_]*ast.FragmentStmt)
+	p.operationStmts = make(map[sdl.NameValue_]*ast.OperationStmt)
+	p.varUses = make(map[*sdl.InputValue_]*varUse)
+	p.varUsesByStmt = make(map[ast.GQLStmtProvider][]*varUse)
+
+	p.doc = &ast.Document{}
+	return p.doc
+}
+
+// parseStatements parses all statements (query, fragment) in the document into api and adds each
+// statement without errors to the cache. failed reports whether any statement was abandoned.
+func (p *Parser) parseStatements(api *ast.Document) (failed bool, allErrors []error) {
+	for p.curToken.Type != token.EOF {
+		//
+		var stmt *ast.Statement
+		p.stmtVarUses = nil
+		start, lbraces := p.curToken.Loc, p.lbraces
+		stmtAST, stmtType := p.parseStatement()
+		if stmtAST == nil || p.hasError() {
+			//
+			// abandon the statement and resume at the next one, so the syntax errors of every statement are reported
+			//
+			failed = true
+			allErrors = append(allErrors, p.perror...)
+			p.perror, p.abort = nil, false
+			p.resync(start, lbraces)
+			continue
+		}
+		stmt = &ast.Statement{Type: stmtType, AST: stmtAST, Name: string(stmtAST.StmtName())}
+		api.Statements = append(api.Statements, stmt)
+		p.varUsesByStmt[stmtAST] = p.stmtVarUses
+		p.log.Log(logger.Debug, "parsed statement", "type", stmtType, "name", stmt.Name)
+		p.stmtCache.addEntry(stmt.AST.StmtName(), stmt.AST) //	ast.Add2StmtCache(stmt.AST.StmtName(), stmt.AST)
+		allErrors = append(allErrors, p.perror...)
+		p.perror = nil
 	}
-	return resultJson, allErrors
+	return failed, allErrors
+}
+
+// Parse parses the statements of the document without validating them against a schema, so no schema
+// store is consulted. Variable references are kept as ast.Variable_ values and an empty list as an
+// empty sdl.List_, so the document describes the source as written, e.g. for printing. The document is nil
+// when there are errors.
+func (p *Parser) Parse() (*ast.Document, []error) {
+	p.syntax = true
+	defer func() { p.syntax = false }()
+	api := p.newDocument()
+	if failed, errs := p.parseStatements(api); failed || len(errs) > 0 {
+		return nil, errs
+	}
+	return api, nil
+}
+
+// Document returns the document of the last call to ParseDocument or Parse, including when they report errors
+// and return none. It holds the statements that parsed, which may not all have been validated, so a field of
+// a document with errors may have no SDL definition.
+func (p *Parser) Document() *ast.Document {
+	return p.doc
+}
+
+// operationName returns the name of an operation statement, which is empty for a shorthand statement.
+func operationName(name string) string {
+	if strings.HasPrefix(name, noName) {
+		return ""
+	}
+	return name
+}
+
+func firstErr(errs []error) error {
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
 }
 
 // ==================== End  =========================
@@ -558,12 +687,70 @@ func (p *Parser) parseStatement() (ast.GQLStmtProvider, string) {
 	return nil, ""
 }
 
+// resync skips the remaining tokens of a statement that failed to parse, stopping at the start of the next
+// statement - an operation or fragment keyword, or the { of a shorthand query - outside any selection set.
+// start is the position of the first token of the failed statement and lbraces the count of { read before it.
+func (p *Parser) resync(start token.Pos, lbraces int) {
+	if p.curToken.Loc == start && p.curToken.Type != token.EOF {
+		// the statement read nothing
+		p.nextToken()
+	}
+	if p.lbraces == lbraces && p.depth == 0 {
+		// failed before its selection set - find it, unless another statement comes first
+		for p.curToken.Type != token.EOF && p.curToken.Type != token.LBRACE && !p.stmtStart() {
+			p.nextToken()
+		}
+	}
+	if p.depth > 0 {
+		// read over the rest of the statement's selection set
+		for p.curToken.Type != token.EOF && p.depth > 0 {
+			p.nextToken()
+		}
+		if p.curToken.Type == token.RBRACE {
+			p.nextToken()
+		}
+	}
+	for p.curToken.Type != token.EOF && !p.stmtStart() && !(p.curToken.Type == token.LBRACE && p.depth == 1) {
+		p.nextToken()
+	}
+}
+
+// stmtStart reports whether the current token is an operation or fragment keyword outside any selection set.
+func (p *Parser) stmtStart() bool {
+	if p.depth > 0 {
+		return false
+	}
+	switch p.curToken.Type {
+	case token.QUERY, token.MUTATION, token.SUBSCRIPTION, token.FRAGMENT:
+		return true
+	}
+	return false
+}
+
 func (p *Parser) SetDocument(doc string) error {
 	p.document = doc
 	//TODO check document exists in db
 	return nil
 }
 
+// SetLogger assigns the logger of the parser and its lexer. Nothing is logged by default.
+func (p *Parser) SetLogger(log logger.Logger) {
+	p.log = log
+	p.stmtCache.log = log
+	p.l.SetLogger(log)
+}
+
+// SetTracer assigns the tracer notified of the phases of each document and its resolver calls.
+// Nothing is traced by default. A tracer that implements tracing.Extender adds its extensions to the response.
+func (p *Parser) SetTracer(t tracing.Tracer) {
+	p.tracer = t
+}
+
+// SetResolverTimeout sets the time a resolver has to respond, replacing the default of ResolverTimeoutMS.
+func (p *Parser) SetResolverTimeout(d time.Duration) {
+	p.resolverTimeout = d
+}
+
 func (p *Parser) SetExecStmt(xStmt string) error {
 	p.xStmt = xStmt
 	return nil
@@ -573,33 +760,27 @@ func (p *Parser) SetExecStmt(xStmt string) error {
 // It is also in the selectionset that objects are sourced and resolved.
 // Once resolved we have the AST of all types referenced to in the operational & fragment (non-type) statements saved in the ql-cache
 //
-func (p *Parser) resolveSDLdependents(stmt ast.GQLStmtProvider, t *pse.Cache_) {
-	fmt.Println("******* resolveSDLdependents.......................")
+func (p *Parser) resolveSDLdependents(stmt ast.GQLStmtProvider, t *typeCache) {
 
 	unresolved := make(sdl.UnresolvedMap) // unresolvedMap: [Name_]*GQLtype
 
 	stmt.SolicitDependents(unresolved)
 
 	resolved := make(ast.UnresolvedMap)
+	t.Lock()
 	for tyName := range unresolved {
-		if _, ok := t.Cache[tyName.String()]; ok {
+		if _, ok := t.cache[tyName.String()]; ok {
 			resolved[tyName] = nil
 			//delete(unresolved, tyName)
 		}
 	}
+	t.Unlock()
 	//  unresolved should only contain non-scalar types known upto this point.
 	for tyName, gqltype := range unresolved {
 
-		ast_, err := t.FetchAST(tyName.Name)
+		ast_, err := t.FetchAST(tyName.Name, p.log)
 		if err != nil {
-			switch {
-			case errors.Is(err, pse.ErrNotCached):
-				p.addErr2(fmt.Errorf(`%q %s in document %q %s %w`, tyName, err, db.GetDocument(), tyName.AtPosition(), TypeResolveErr))
-			case errors.Is(err, db.NoItemFoundErr):
-				p.addErr2(fmt.Errorf(`%s %s %w`, err, tyName.AtPosition(), TypeResolveErr))
-			default:
-				p.addErr2(fmt.Errorf(`%s %s %w`, err, tyName.AtPosition(), TypeResolveErr))
-			}
+			p.addErr2(fmt.Errorf(`%s %s %w`, err, tyName.AtPosition(), TypeResolveErr))
 		} else {
 			//
 			//set AST in gpltype if not already assigned
@@ -615,8 +796,6 @@ func (p *Parser) resolveSDLdependents(stmt ast.GQLStmtProvider, t *pse.Cache_) {
 
 func (p *Parser) checkFields(root sdl.GQLTypeProvider, stmt_ ast.GQLStmtProvider) {
 
-	p.responseMap = make(map[string]*sdl.InputValueProvider) // map[sdl.NameValue_]map[sdl.NameValue_]sdl.GQLTypeProvider
-
 	switch stmt := stmt_.(type) {
 
 	case *ast.OperationStmt:
@@ -629,34 +808,39 @@ func (p *Parser) checkFields(root sdl.GQLTypeProvider, stmt_ ast.GQLStmtProvider
 		//
 		// validate directives
 		//
-		pse.LoadASTcache(p.tyCache)
+		p.tyCache.loadTyCache()
 		p.validateDirectives(stmt.Directives, root, sdl.QUERY_DL, stmt.Name)
 		//
 		// validate stmt fields
 		//
 		p.checkFields_(root, stmt.SelectionSet, string(root.TypeName()))
+		if len(p.perror) == 0 {
+			p.checkFieldMerging(root, stmt.SelectionSet, make(map[string]bool))
+		}
 
 	case *ast.FragmentStmt:
 		//
 		// fragment  FragmentName  TypeCondition  Directives-opt  SelectionSet
 		//
-		fmt.Println("Fragment stmt")
 		var err error
-		root, err = p.tyCache.FetchAST(stmt.TypeCond.Name)
+		root, err = p.tyCache.FetchAST(stmt.TypeCond.Name, p.log)
 		if err != nil {
 			p.addErr(err.Error())
 		}
 		// validate on type condition of fragment
-		stmt.AssignTypeCondAST(&p.perror, p.tyCache)
+		stmt.AssignTypeCondAST(&p.perror, typeFetcher{p.tyCache, p.log})
 		//
 		// validate directives
 		//
-		pse.LoadASTcache(p.tyCache)
+		p.tyCache.loadTyCache()
 		p.validateDirectives(stmt.Directives, root, sdl.FRAGMENT_DEFINITION_DL, stmt.Name)
 		//
 		// validate stmt fields
 		//
 		p.checkFields_(root, stmt.SelectionSet, string(root.TypeName()))
+		if len(p.perror) == 0 {
+			p.checkFieldMerging(root, stmt.SelectionSet, make(map[string]bool))
+		}
 	}
 }
 
@@ -677,7 +861,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 		//p.addErr("In checkFields_, passed in a root of nil")
 		panic(fmt.Errorf("In checkFields_, passed in a root of nil"))
 	}
-	fmt.Println("***************************************** Enter checkFields_ *********************************************** ", root.TypeName(), root.Type())
+	p.log.Log(logger.Debug, "check fields", "root", root.TypeName(), "path", pathRoot)
 	// fieldset referenced in ql Stmt
 	for _, qryFld := range set { // allPersons(last:3)
 
@@ -714,7 +898,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 					///
 					// first load ast cache -
 					//
-					pse.LoadASTcache(p.tyCache) // TODO - this is executed on every recursuive call - try and relocate so only called once.
+					p.tyCache.loadTyCache() // TODO - this is executed on every recursuive call - try and relocate so only called once.
 					//
 					// Validate arguments in QL field against sdl definition
 					//
@@ -743,38 +927,45 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 					switch x := sdlFld.Type.AST.(type) {
 
 					case *sdl.Object_:
-						fmt.Println("matching sdl type is an object")
 						sdlTypeAST = x
 						var fieldPath string
 						fieldPath = pathRoot + "/" + qry.GenNameAliasPath() //+ "/" + root.TypeName().String()
-						fmt.Println("************** fieldPath", fieldPath, sdlTypeAST.TypeName())
+						p.log.Log(logger.Debug, "check field", "path", fieldPath, "type", sdlTypeAST.TypeName())
 						p.respOrder = append(p.respOrder, fieldPath)
-						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()))
+						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()), p.log)
 
 						p.checkFields_(sdlTypeAST, qry.SelectionSet, fieldPath)
 
 					case *sdl.Interface_:
-						fmt.Println("matching sdl type is an interface")
 						sdlTypeAST = x
 						var fieldPath string
 						fieldPath = pathRoot + "/" + qry.GenNameAliasPath() //+ "/" + root.TypeName().String()
-						fmt.Println("************** fieldPath", fieldPath, sdlTypeAST.TypeName())
+						p.log.Log(logger.Debug, "check field", "path", fieldPath, "type", sdlTypeAST.TypeName())
 						p.respOrder = append(p.respOrder, fieldPath)
-						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()))
+						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()), p.log)
 
 						p.checkFields_(sdlTypeAST, qry.SelectionSet, fieldPath)
 
 					case *sdl.Union_:
-						fmt.Println("matching sdl type is a Union")
 						sdlTypeAST = x
 						var fieldPath string
 						fieldPath = pathRoot + "/" + qry.GenNameAliasPath() //+ "/" + root.TypeName().String()
-						fmt.Println("************** fieldPath", fieldPath, sdlTypeAST.TypeName())
+						p.log.Log(logger.Debug, "check field", "path", fieldPath, "type", sdlTypeAST.TypeName())
 						p.respOrder = append(p.respOrder, fieldPath)
-						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()))
+						p.tyCache.FetchAST(sdl.NameValue_(sdlTypeAST.TypeName()), p.log)
 
 						p.checkFields_(sdlTypeAST, qry.SelectionSet, fieldPath)
 
+					default:
+						//
+						// scalar or enum - a leaf field
+						//
+						if len(qry.SelectionSet) != 0 {
+							p.addErr(fmt.Sprintf(`Field "%s" must not have a selection since type "%s" has no subfields %s`, qry.Name, sdlFld.Type.String(), qry.Name.AtPosition()))
+						}
+					}
+					if sdlTypeAST != nil && len(qry.SelectionSet) == 0 {
+						p.addErr(fmt.Sprintf(`Field "%s" of type "%s" must have a selection of subfields. Did you mean "%s { ... }"? %s`, qry.Name, sdlFld.Type.String(), qry.Name, qry.Name.AtPosition()))
 					}
 					//	qry.Type = sdlFld.Type // assign sdl Type to *ast.Field
 					qry.SDLRootAST = root
@@ -782,9 +973,8 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 
 					if !(sdlTypeAST != nil && len(qry.SelectionSet) != 0) { // TODO - remove this check as is redundant
 						//
-						// scalar field - append to response map
+						// scalar field - append to response order. Repeated fields are checked by checkFieldMerging
 						//
-						fmt.Println("scalar field - append to response map ")
 						var fieldPath strings.Builder
 						fieldPath.WriteString(pathRoot)
 						// fieldPath.WriteString("/")
@@ -796,13 +986,8 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 						// 	fieldPath.WriteString(qry.Alias.String())
 						// }
 						//	qryFldMap[fieldPath] = sdlFld
-						fmt.Println("Scalar fieldPath: ", fieldPath.String())
-						if _, ok := p.responseMap[fieldPath.String()]; ok {
-							p.addErr(fmt.Sprintf(`Field "%s.%s" has already been specified %s`, root.TypeName(), fieldPath.String(), qry.Name.AtPosition()))
-						} else {
-							p.responseMap[fieldPath.String()] = nil
-							p.respOrder = append(p.respOrder, fieldPath.String())
-						}
+						p.log.Log(logger.Debug, "check field", "path", fieldPath.String(), "type", sdlFld.Type.Name)
+						p.respOrder = append(p.respOrder, fieldPath.String())
 					}
 					break
 				}
@@ -840,7 +1025,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			//
 			root := root
 			pathRoot := pathRoot
-			fmt.Println("checkFields_ : for Fragment Spread - qry.Name ", qry.Name)
+			p.log.Log(logger.Debug, "check fragment spread", "name", qry.Name, "path", pathRoot)
 			//get associated Fragment statement AST, via the FragmentSpread Name
 			stmtAST := p.stmtCache.fetchAST(ast.StmtName_(qry.Name.String()))
 			if stmtAST == nil {
@@ -914,20 +1099,20 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 					}
 					root = q
 
-				case *sdl.Interface_:
-					// are there common fields in the interfaces?
-				case *sdl.Union_:
-					// do the members of the union implment the interface.
+				case *sdl.Interface_, *sdl.Union_:
+					// is there an object that implements both interfaces, or a member of the union that implements the interface?
+					p.checkSpreadPossible(qry, r, q)
+					root = q
 				}
 
 			case *sdl.Union_:
 
-				switch qry.FragStmt.TypeCondAST.(type) {
+				switch q := qry.FragStmt.TypeCondAST.(type) {
 
-				case *sdl.Object_:
-					//   is object a member of union?
-				case *sdl.Interface_, *sdl.Union_:
-					// invalid = spec: members of a union must be object based.
+				case *sdl.Object_, *sdl.Interface_, *sdl.Union_:
+					// is the object a member of the union, or does a member implement the interface or belong to both unions?
+					p.checkSpreadPossible(qry, r, q)
+					root = q
 				}
 			}
 			//
@@ -935,7 +1120,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			///
 			// first load ast cache -
 			//
-			pse.LoadASTcache(p.tyCache) // TODO - this is executed on every recursuive call - try and relocate so only called once.
+			p.tyCache.loadTyCache() // TODO - this is executed on every recursuive call - try and relocate so only called once.
 			//
 			// Validate directives
 			//
@@ -943,7 +1128,6 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			if p.hasError() {
 				return
 			}
-			fmt.Println("Fragment spread: qry.Name_ , pathRoot", qry.Name_, pathRoot)
 
 			p.checkFields_(root, qry.FragStmt.GetSelectionSet(), pathRoot)
 
@@ -962,7 +1146,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			root := root
 			//pathRoot := pathRoot
 			//pathRoot += "/" + qry.TypeCond.String()
-			fmt.Println("checkFields_ : for Inline fragment Spread - qry.Name ", qry.Name, qry.TypeCond, pathRoot, root.TypeName(), root.Type())
+			p.log.Log(logger.Debug, "check inline fragment", "typeCondition", qry.TypeCond, "path", pathRoot)
 			//
 			if !qry.TypeCond.Exists() {
 				//
@@ -984,7 +1168,7 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 				}
 			} else {
 				// check cond type is appropriate i.e object, interface, union. Assign type cond AST.
-				qry.AssignTypeCondAST(&p.perror, p.tyCache)
+				qry.AssignTypeCondAST(&p.perror, typeFetcher{p.tyCache, p.log})
 				// A selection set of the inline-fragment exists at the level of the enclosing type (root)
 				// for inline-fragments with type condition specified the root morphs from the union/interfae of the enclosing
 				// to the type of the type-condition.
@@ -993,113 +1177,45 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			//
 			//pathRoot += "/" + qry.TypeCond.String()	// removed to fix test TestRootInterfaceWithOnlineFragmentDupFields.
 			//
-			// compare field's enclosing type (root) against fields type and on-condition if specified
+			// compare field's enclosing type (fragRoot) against the on-condition if specified
 			//
-			switch x := root.(type) {
-
-			case *sdl.Union_:
-				//
-				// field type must support Union.
-				//
-				if !qry.TypeCond.Exists() {
-					p.addErr(fmt.Sprintf(`Inline fragment has no on-condition type specified when enclosing Union type, %q, requires that it have one, %s`, root.TypeName(), qry.Name_.AtPosition()))
-					return
-				} else {
-					// check type cond satisifies union
-					var found bool
-					for _, v := range x.NameS {
-						if v.Equals(qry.TypeCond) {
-							found = true
-						}
-					}
-					if !found {
-						p.addErr(fmt.Sprintf(`%q on condition type not a member of union type, "%s" %s`, qry.TypeCond.String(), root.TypeName(), qry.Name_.AtPosition()))
-						return
-					}
+			if qry.TypeCond.Exists() {
+				switch root.(type) {
+				case *sdl.Object_, *sdl.Union_, *sdl.Interface_:
+				default:
+					// reported by AssignTypeCondAST
+					continue
 				}
-
-			case *sdl.Interface_:
-				var found bool
-
-				if !qry.TypeCond.Exists() {
-					if len(qry.Directives) == 0 {
-						p.addErr(fmt.Sprintf(`Enclosing type for an inline fragment field must specify a type on-condition. %s`, qry.Name_.AtPosition()))
-					}
-				} else {
-					//
-					// does the field's type implement the interface
-					//
-					switch fld := qry.TypeCondAST.(type) {
-
-					case *sdl.Object_:
-						for _, v := range fld.Implements {
-							if v.EqualString(root.TypeName().String()) {
-								found = true
-								break
-							}
-						}
-						if !found {
-							p.addErr(fmt.Sprintf(`On condition type %q does not implement interface %q, %s`, qry.TypeCond.String(), fragRoot.TypeName(), qry.Name_.AtPosition()))
-							continue
-						}
-
-					default:
-						if len(qry.Directives) == 0 {
-							p.addErr(fmt.Sprintf(`Inline fragment field must specify a type on-condition. %s`, qry.Name_.AtPosition()))
-						}
-					}
+				// as for a fragment spread, some object must be of both the enclosing type and the on-condition type
+				if !p.checkInlinePossible(qry, fragRoot, root) {
+					continue
 				}
-
-			case *sdl.Object_:
-				// inline fragment applies to root type for nil on-condition only
-
-				switch z := fragRoot.(type) {
+			} else {
+				switch x := root.(type) {
 
 				case *sdl.Union_:
-					// verify object type is member of union
-					var found bool
-					for _, v := range z.NameS {
-						if v.Equals(qry.TypeCond) {
-							found = true
-						}
-					}
-					if !found {
-						p.addErr(fmt.Sprintf(`%q on condition type not a member of union type, "%s" %s`, qry.TypeCond.String(), root.TypeName(), qry.Name_.AtPosition()))
-						return
-					}
+					p.addErr(fmt.Sprintf(`Inline fragment has no on-condition type specified when enclosing Union type, %q, requires that it have one, %s`, root.TypeName(), qry.Name_.AtPosition()))
+					return
 
 				case *sdl.Interface_:
-					// check object implements interface
-					// verify object type is member of union
-					var found bool
-					for _, v := range x.Implements {
-						if v.EqualString(z.TypeName().String()) {
-							found = true
-							break
-						}
-					}
-					if !found {
-						p.addErr(fmt.Sprintf(`On condition type %q does not implement interface %q, %s`, qry.TypeCond.String(), fragRoot.TypeName(), qry.Name_.AtPosition()))
-						continue
-					}
 
 				case *sdl.Object_:
 					// inline fragment applies to root type for nil on-condition only
-					if len(qry.Directives) == 0 {
+					if _, ok := fragRoot.(*sdl.Object_); ok && len(qry.Directives) == 0 {
 						p.addErr(fmt.Sprintf(`Enclosing type for an inline fragment field must be an Interface or Union if type on-condition specified or Object type if none. Got %q %s`, x.Type(), qry.Name_.AtPosition()))
 						return
 					}
-				}
 
-			default:
-				if len(qry.Directives) == 0 {
-					p.addErr(fmt.Sprintf(`Enclosing type for an inline fragment field must be an Interface or Union if type on-condition specified or Object type if none. Got %q %s`, x.Type(), qry.Name_.AtPosition()))
+				default:
+					if len(qry.Directives) == 0 {
+						p.addErr(fmt.Sprintf(`Enclosing type for an inline fragment field must be an Interface or Union if type on-condition specified or Object type if none. Got %q %s`, x.Type(), qry.Name_.AtPosition()))
+					}
 				}
 			}
 			///
 			// first load ast cache -
 			//
-			pse.LoadASTcache(p.tyCache) // TODO - this is executed on every recursuive call - try and relocate so only called once.
+			p.tyCache.loadTyCache() // TODO - this is executed on every recursuive call - try and relocate so only called once.
 			//
 			// Validate directives in QL field against sdl Def
 			//
@@ -1107,13 +1223,11 @@ func (p *Parser) checkFields_(root sdl.GQLTypeProvider, set []ast.SelectionSetPr
 			if p.hasError() {
 				return
 			}
-			fmt.Println("InlineFragment: sset , pathRoot", qry.SelectionSet, pathRoot)
 
 			p.checkFields_(root, qry.SelectionSet, pathRoot)
 
 		} // switch
 	}
-	fmt.Println("++++++++++++++++++++++++++++++++++++++++++++ leave  checkFields_ --------------------------------------* ")
 
 }
 
@@ -1154,34 +1268,57 @@ func (p *Parser) executeStmt(stmt_ *ast.Statement) string {
 		p.addErr(fmt.Sprintf("Expected an Query OperationStmt in execute phase. Aborting. "))
 		return ""
 	}
+	p.bindVariables(stmt)
+	if p.checkVariableValues(stmt); p.hasError() {
+		return ""
+	}
+	//
+	// collect the root fields by response key, expanding the fragments of the root selection set.
+	//
+	root := stmt_.RootAST
+	var fields []*collectedField
+	if !p.collectFields(stmt.SelectionSet, string(root.TypeName()), string(root.TypeName()), &fields, make(map[string]*collectedField)) {
+		return ""
+	}
 	//
 	// concurrently execute stmt roots (graph entry point defined in schema) - concurrent safe
 	//
-	wg.Add(len(stmt.SelectionSet))
+	wg.Add(len(fields))
 
-	out = make([]strings.Builder, len(stmt.SelectionSet), len(stmt.SelectionSet))
-	root := stmt_.RootAST
+	out = make([]strings.Builder, len(fields), len(fields))
+	null := make([]bool, len(fields))
 
-	for i, opFld := range stmt.SelectionSet {
-		opFld := opFld
-		go p.executeStmtOp(opFld, string(root.TypeName()), nil, &out[i], &wg)
+	for i, cf := range fields {
+		// the field of the response key with the sub-selections of every field of the key
+		opFld := *cf.field
+		opFld.SelectionSet = cf.selectionSet
+		go p.executeStmtOp(&opFld, cf.pathRoot, nil, &out[i], &null[i], &wg)
 	}
 
 	wg.Wait()
 	//
 	// combine stmt outputs
 	//
+	finish := p.tracer.Start(tracing.Span{Kind: tracing.Serialize})
+	defer finish(nil)
 	var ts strings.Builder
-	if len(stmt.SelectionSet) > 1 {
+	for _, n := range null {
+		if n {
+			// a root field that may not be null is null
+			ts.WriteString("\n{\ndata: null\n}")
+			return ts.String()
+		}
+	}
+	if len(fields) > 1 {
 		ts.WriteString(" { data : [ ")
 	} else {
 		ts.WriteString("\n{\ndata: {")
 	}
 
-	for i, _ := range stmt.SelectionSet {
+	for i, _ := range fields {
 		ts.WriteString(out[i].String())
 	}
-	if len(stmt.SelectionSet) > 1 {
+	if len(fields) > 1 {
 		ts.WriteString(" \n ] } ")
 	} else {
 		ts.WriteString("\n}\n}")
@@ -1190,25 +1327,154 @@ func (p *Parser) executeStmt(stmt_ *ast.Statement) string {
 
 }
 
+// Use appends middleware to the chain run around the resolution of every field, whether by a
+// registered resolver or from the response data of its parent. Middleware added first runs outermost.
+func (p *Parser) Use(mw ...resolver.Middleware) {
+	p.middleware = append(p.middleware, mw...)
+}
+
+// resolveField resolves query field qry using fn within the middleware chain. parent is the response data of the field's parent.
+func (p *Parser) resolveField(qry *ast.Field, fieldPath string, parent sdl.InputValueProvider, fn resolver.FieldResolveFn) (sdl.InputValueProvider, error) {
+
+	f := &resolver.Field{Path: fieldPath, Name: qry.Name.String(), Arguments: qry.Arguments, Parent: parent, Resolver: qry.Resolver}
+	if qry.Alias.Exists() {
+		f.Alias = qry.Alias.String()
+	}
+	f.ParentType = parentTypeName(qry)
+	return resolver.Chain(fn, p.middleware...)(p.ctx, f)
+}
+
+// parentTypeName returns the name of the SDL type query field qry belongs to.
+func parentTypeName(qry *ast.Field) string {
+	if qry.SDLRootAST == nil {
+		return ""
+	}
+	return qry.SDLRootAST.TypeName().String()
+}
+
+// resolveDefault resolves a field without a resolver from the response data of its parent.
+// The value is nil if the parent has no data for the field.
+func resolveDefault(ctx context.Context, f *resolver.Field) (sdl.InputValueProvider, error) {
+
+	parent := f.Parent
+	if iv, ok := parent.(*sdl.InputValue_); ok {
+		// typical response from Union member field. response name field matches inline fragment "on" clause.
+		parent = iv.InputValueProvider
+	}
+	if vals, ok := parent.(sdl.ObjectVals); ok {
+		for _, v := range vals {
+			if v.Name_.EqualString(f.Name) {
+				return v.Value.InputValueProvider, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// callResolver returns a FieldResolveFn that executes the field's resolver with response data resp,
+// waiting at most the resolver timeout, and parses its response. The call is traced as a Resolve span.
+func (p *Parser) callResolver(qry *ast.Field, resp sdl.InputValueProvider) resolver.FieldResolveFn {
+
+	return func(ctx context.Context, f *resolver.Field) (sdl.InputValueProvider, error) {
+
+		ctx, cancel := context.WithTimeout(ctx, p.resolverTimeout)
+		defer cancel()
+		span := tracing.Span{Kind: tracing.Resolve, Path: f.Path, ParentType: f.ParentType, FieldName: qry.SDLfld.Name_.String(), ReturnType: qry.SDLfld.Type.String()}
+		if i := strings.Index(f.Path, "/"); i >= 0 {
+			// drop the root type from the path
+			span.Path = f.Path[i+1:]
+		}
+		finish := p.tracer.Start(span)
+
+		fldNm := f.Name
+		if len(f.Alias) > 0 {
+			fldNm = f.Alias
+		}
+		var (
+			response string
+			err      error
+		)
+		p.log.Log(logger.Debug, "execute resolver", "path", f.Path)
+		rch := f.Resolver(ctx, resp, f.Arguments) // arguments -> sdl.ObjectVals as both share common def []*ArgumentT
+		//
+		// blocking wait
+		//
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf(`Resolver for "%s" timed out after %s: %w`, fldNm, p.resolverTimeout, ctx.Err())
+		case response = <-rch:
+			if len(response) == 0 {
+				err = fmt.Errorf(`Resolver for "%s" successfully returned but produced no content`, fldNm)
+			}
+		}
+		p.log.Log(logger.Debug, "resolver response", "path", f.Path, "response", response)
+		if err != nil {
+			finish(err)
+			return nil, err
+		}
+		//
+		// generate AST from response JSON { name: value name: value ... }
+		//
+		p2 := pse.New(lex.New(response))
+		respItems := p2.ParseResponse() // similar to sdl.parseArguments. Populates responseItems with parsed values from response.
+		if errs := p2.Getperror(); len(errs) > 0 {
+			// parse errors are recorded against the statement, which is aborted once the response is processed.
+			p.log.Log(logger.Warn, "resolver response parse error", "path", f.Path, "errors", errs)
+			p.perrorMx.Lock()
+			p.perror = append(p.perror, errs...)
+			p.perrorMx.Unlock()
+			finish(errs[0])
+		} else {
+			finish(nil)
+		}
+		if respItems == nil {
+			return nil, fmt.Errorf(`Empty response from resolver for "%s"`, fldNm)
+		}
+		return respItems, nil
+	}
+}
+
 // executeStmtOp executes an operational statement. Multiple stmts can be executed concurrently as does method, executeStmt
 //
-func (p *Parser) executeStmtOp(qryFld ast.SelectionSetProvider, pathRoot string, responseItems sdl.InputValueProvider, out *strings.Builder, wg *sync.WaitGroup) {
+// null is set when a field that may not be null is null, which makes the data of the response null.
+func (p *Parser) executeStmtOp(qryFld ast.SelectionSetProvider, pathRoot string, responseItems sdl.InputValueProvider, out *strings.Builder, null *bool, wg *sync.WaitGroup) {
 
 	var sset = []ast.SelectionSetProvider{qryFld}
 	var responseType string = ""
-	p.executeStmt_(sset, pathRoot, responseType, responseItems, out)
+	*null = p.executeStmt_(sset, pathRoot, nil, responseType, responseItems, out)
 	wg.Done()
 }
 
-func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot string, responseType string, responseItems sdl.InputValueProvider, out *strings.Builder) { //type ObjectVals []*ArgumentT - serialized object
-	// *******************									 *******************
-	// ******************* This method is concurrency safe.  *******************
-	// *******************									 *******************
-	//
-	// gqlsset: 		 GQl-selection-set (*ast.Field, *ast.InlineFragment etc) starting with type Query (from schema query)
-	// pathRoot:	 the path (concatenation of field names) taken to get to current field
+// writeObject writes the object of response data items, or null if a field of the object that may not be null
+// is null, which it reports. sset is the selection set of the object.
+func (p *Parser) writeObject(sset []ast.SelectionSetProvider, fieldPath string, respPath []interface{}, responseType string, items sdl.InputValueProvider, out *strings.Builder, noNewLine ...bool) bool {
+	var obj strings.Builder
+	if p.executeStmt_(sset, fieldPath, respPath, responseType, items, &obj) {
+		writeout(fieldPath, out, "null", noNewLine...)
+		return true
+	}
+	writeout(fieldPath, out, "{", noNewLine...)
+	out.WriteString(obj.String())
+	writeout(fieldPath, out, "}", noNewLine...)
+	return false
+}
+
+// nonNull reports whether the value of type t at list depth d, 0 being the value of the field, may not be null.
+func nonNull(t *sdl.GQLtype, d uint8) bool {
+	return t.Constraint>>(t.Depth-d)&1 == 1
+}
+
+func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot string, respPath []interface{}, responseType string, responseItems sdl.InputValueProvider, out *strings.Builder) (null bool) { //type ObjectVals []*ArgumentT - serialized object
+	// *******************									 *******************
+	// ******************* This method is concurrency safe.  *******************
+	// *******************									 *******************
+	//
+	// gqlsset: 		 GQl-selection-set (*ast.Field, *ast.InlineFragment etc) starting with type Query (from schema query)
+	// pathRoot:	 the path (concatenation of field names) taken to get to current field
+	// respPath:	 the response path of the selection set, the field names (or aliases) and list indexes of the response data
 	// responseType: RSV: type of the resolver response data as described in the data metadata or from the current Field type.
 	// responseItems: RSVL: response data in the form of InputValue_ type
+	// null:		 reports a field that may not be null is null, so the object of the selection set is null
 	//
 	// 	stmt:	`query XYZ {												<== query statement (what to display)
 	//      allPersons(last: 2 ) {											<== resolver here - generates data below
@@ -1339,887 +1605,883 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 	)
 
 	addErr := func(s string, abort ...bool) {
-		p.Lock()
-		p.addErr(s, abort[0])
-		p.Unlock()
+		p.perrorMx.Lock()
+		p.addErr(s, abort...)
+		p.perrorMx.Unlock()
+	}
+	addErrs := func(errs []error) {
+		p.perrorMx.Lock()
+		p.perror = append(p.perror, errs...)
+		p.perrorMx.Unlock()
+	}
+	expandArguments := func(qry *ast.Field, sdlFld *sdl.Field_) bool {
+		var errs []error
+		failed := qry.ExpandArguments(sdlFld, &errs)
+		addErrs(errs)
+		return failed
 	}
 
 	p.perrorMx.Lock()
 	if p.hasError() {
+		p.perrorMx.Unlock()
 		return
 	}
 	p.perrorMx.Unlock()
 
-	for _, qryFld := range gqlsset {
-
-		fmt.Println("++++++++++++++++++++++++++++++++++. TOP OF LOOP ===================================================")
+	var fields []*collectedField
+	if !p.collectFields(gqlsset, pathRoot, responseType, &fields, make(map[string]*collectedField)) {
+		return
+	}
+	for _, cf := range fields {
 
 		// objective is to compare the query field and its associated SDL type (populated during parsing) with the resolver's response data
+		//
+		// sset is the merged selection set of every field of the response key
+		//
+		qry, pathRoot, sset := cf.field, cf.pathRoot, cf.selectionSet
 
-		switch qry := qryFld.(type) {
+		// ast.Field.Name = AllPersons, ast.Field.SDLfld = Person
+		// ast.Field.Name = Age	, ast.Field.SDLfld = Int
+		// ast.Field.Name = posts, ast.Field.SDLfld = Post
+		//	fmt.Printf("\n\n*** Query field: %#v\n", qry)
+		var (
+			sdlTypeAST sdl.GQLTypeProvider
+			fieldPath  string
+			fieldName  string
+			sdlFld     *sdl.Field_
+		)
+		// SDLfld & SDLRootAST populated during parsing CheckField
+		//
+		if qry.SDLfld == nil {
+			err := fmt.Errorf(`SDLfld for field "%s" not assigned. Abort`, qry.Name)
+			panic(err)
+		}
+		//
+		// sdlFld is the SDL type for the current ast.Field e.g. gql's "allPerson" has a sdl type of "[Person!]". It is populated during parsing.
+		//
+		sdlFld = qry.SDLfld
+		//
+		//	fmt.Println("\n ============================ sdlFld =============================", qry.Name, sdlFld.Name_, qry.SDLRootAST.TypeName())
 
-		case *ast.Field:
-			// ast.Field.Name = AllPersons, ast.Field.SDLfld = Person
-			// ast.Field.Name = Age	, ast.Field.SDLfld = Int
-			// ast.Field.Name = posts, ast.Field.SDLfld = Post
-			//	fmt.Printf("\n\n*** Query field: %#v\n", qry)
-			var (
-				sdlTypeAST sdl.GQLTypeProvider
-				fieldPath  string
-				fieldName  string
-				response   string
-				sdlFld     *sdl.Field_
-			)
-			// SDLfld & SDLRootAST populated during parsing CheckField
-			//
-			if qry.SDLfld == nil {
-				err := fmt.Errorf(`SDLfld for field "%s" not assigned. Abort`, qry.Name)
-				panic(err)
+		if qry.Alias.Exists() {
+			fieldName = qry.Alias.String()
+		} else {
+			fieldName = qry.Name.String()
+		}
+		// response path of the field, extended with the index of each list item
+		fieldRespPath := append(respPath[:len(respPath):len(respPath)], fieldName)
+		//
+		// fields denied to the principal by the @auth directive are null in the response. Checked before any resolver runs.
+		// A field that may not be null makes its parent null.
+		//
+		if err := auth.Authorize(p.ctx, parentTypeName(qry), sdlFld); err != nil {
+			writeout(pathRoot, out, fieldName)
+			writeout(pathRoot, out, ":", noNewLine)
+			writeout(pathRoot, out, "null", noNewLine)
+			p.addFieldErr(fieldRespPath, auth.Forbidden, err)
+			if nonNull(sdlFld.Type, 0) {
+				return true
 			}
+			continue
+		}
+		//
+		// associated SDL type of the ast.Field
+		//
+		// AST is nil for scalars
+		switch sdlFld.Type.AST.(type) {
+
+		case *sdl.Object_, *sdl.Interface_, *sdl.Union_:
 			//
-			// sdlFld is the SDL type for the current ast.Field e.g. gql's "allPerson" has a sdl type of "[Person!]". It is populated during parsing.
+			//  -- AAA ----
 			//
-			sdlFld = qry.SDLfld
+			// object field, details in AST (as it is not a scalar)
 			//
-			//	fmt.Println("\n ============================ sdlFld =============================", qry.Name, sdlFld.Name_, qry.SDLRootAST.TypeName())
+			sdlTypeAST = sdlFld.Type.AST
+			fieldPath = pathRoot + "/" + sdlFld.Name_.String()
 
-			if qry.Alias.Exists() {
-				fieldName = qry.Alias.String()
-			} else {
-				fieldName = qry.Name.String()
-			}
-			//
-			// associated SDL type of the ast.Field
-			//
-			// AST is nil for scalars
-			switch sdlFld.Type.AST.(type) {
+			// fmt.Println("********** sdlTypeAST(typename).  ", sdlTypeAST.TypeName())
+			// fmt.Println("**********  pathRooth: ", sdlFld.Name_.String())
+			// fmt.Println("**********  fieldPath: ", fieldPath)
+			// fmt.Println("**********  qry. Name: ", qry.Name)
 
-			case *sdl.Object_, *sdl.Interface_, *sdl.Union_:
+			qry.Resolver = p.Resolver.GetFunc(fieldPath)
+
+			if qry.Resolver == nil {
 				//
-				//  -- AAA ----
+				// use data from last resolver execution (called a default resolver), passed in via argument "responseItems"
 				//
-				// object field, details in AST (as it is not a scalar)
+				if responseItems == nil {
+					addErr(fmt.Sprintf(`xx No responseItem. Default Resolver must have a responseItem. Field "%s" has no resolver function, %s %s`, qry.Name, sdlFld.Type.AST.TypeName(), qry.Name.AtPosition()), abort)
+					//p.abort = true
+					return
+				}
 				//
-				sdlTypeAST = sdlFld.Type.AST
-				fieldPath = pathRoot + "/" + sdlFld.Name_.String()
-
-				// fmt.Println("********** sdlTypeAST(typename).  ", sdlTypeAST.TypeName())
-				// fmt.Println("**********  pathRooth: ", sdlFld.Name_.String())
-				// fmt.Println("**********  fieldPath: ", fieldPath)
-				// fmt.Println("**********  qry. Name: ", qry.Name)
-
-				qry.Resolver = p.Resolver.GetFunc(fieldPath)
-
-				if qry.Resolver == nil {
-					//
-					// use data from last resolver execution (called a default resolver), passed in via argument "responseItems"
-					//
-					if responseItems == nil {
-						addErr(fmt.Sprintf(`xx No responseItem. Default Resolver must have a responseItem. Field "%s" has no resolver function, %s %s`, qry.Name, sdlFld.Type.AST.TypeName(), qry.Name.AtPosition()), abort)
-						//p.abort = true
-						return
-					}
-					//
-					// find element in response that matches current query field. RespItem is a InputValue_ type
-					//
-					switch respItem := responseItems.(type) {
-					// response will always be "FieldName:value" pairs e.g. { data: [ { } { } ], where value may be a List_ or another ObjectVal or a scalar
-					// as a result the first (top entry) will always be an ObjectVals type
-					case sdl.ObjectVals:
-						//  { name:value name:value ... } -  type ObjectVals []*ArgumentT   type ArgumentT struct { Name_, Value *InputValue_}   type InputValue {InputValueProvider, Loc}
-						for _, respfld := range respItem {
-
-							if !qry.Name.Equals(respfld.Name_) {
-								continue
-							}
-							//
-							//  found query fields matching response field
-							//
-							writeout(pathRoot, out, fieldName)
-							writeout(pathRoot, out, ":", noNewLine)
-							if _, ok := respfld.Value.InputValueProvider.(sdl.List_); ok {
-								if sdlFld.Type.Depth == 0 {
-									addErr(fmt.Sprintf(`Resolver returned a list of items, expected a single item for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
-									//p.abort = true
-									return
-								}
-							} else {
-								if sdlFld.Type.Depth > 0 {
-									addErr(fmt.Sprintf(`Resolver returned a single value, expected a list for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
-									//p.abort = true
-									return
-								}
-							}
-							switch riv := respfld.Value.InputValueProvider.(type) {
-
-							case sdl.List_:
-								//TODO include nullable check
-								//fmt.Println("+++++ sdlFld.Type.IsType2(), riv.IsType() = ", sdlFld.Type.IsType2(), riv.IsType())
-								if sdlFld.Type.Depth == 0 {
-									addErr(fmt.Sprintf(`Resolver returned a list, expected a single item for "%s" %s`, sdlFld.Name, qry.Name.AtPosition()))
-								}
-
-								var f func(y sdl.List_, d uint8)
-								// f will output sdl.List_ for any level of nesting
-								// d is the nesting depth of List_
-								f = func(y sdl.List_, d uint8) {
-
-									for i := 0; i < len(y); i++ {
-										if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
-											writeout(fieldPath, out, "[ ", noNewLine)
-											d++ // nesting depth of List_
-											if d > sdlFld.Type.Depth {
-												addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
-											}
-											f(x, d)
-											writeout(fieldPath, out, "] ", noNewLine)
-											d--
-										} else {
-											if d < sdlFld.Type.Depth {
-												addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
-											}
-											// optimise by performing loop here rather than use outer for loop
-											for i := 0; i < len(y); i++ {
-
-												writeout(fieldPath, out, "{")
-
-												p.executeStmt_(qry.SelectionSet, fieldPath, responseType, y[i].InputValueProvider, out)
-
-												writeout(fieldPath, out, "}")
-											}
-											break
-										}
-									}
-								}
-
-								writeout(fieldPath, out, "[ ", noNewLine)
-								f(riv, 1)
-								writeout(fieldPath, out, "] ", noNewLine)
-
-							case sdl.ObjectVals:
-								//
-								if sdlFld.Type.Depth != 0 {
-									addErr(fmt.Sprintf(`Expected List of values for "%s", resolver response returned single value %s`, sdlFld.Name, qry.Name.AtPosition()))
-								}
-								//TODO include nullable check
-								if sdlFld.Type.IsType() != riv.IsType() {
-									addErr(fmt.Sprintf(`2 Expected type of "%s" got %s instead for field "%s" %s`, sdlFld.Type.IsType(), riv.IsType(), sdlFld.Name, qry.Name.AtPosition()))
-								}
-								fmt.Printf("== Response is OBJECTVALS of objects/fields .")
-								writeout(fieldPath, out, "{", noNewLine)
-
-								p.executeStmt_(qry.SelectionSet, fieldPath, responseType, riv, out)
-
-								writeout(fieldPath, out, "}", noNewLine)
-
-							default:
-								//
-								if sdlFld.Type.Depth != 0 {
-									addErr(fmt.Sprintf(`Expected List of values for "%s" , resolver response returned single value instead %s`, sdlFld.Name, qry.Name.AtPosition()))
-								}
-								//TODO include nullable check
-								if sdlFld.Type.IsType() != riv.IsType() {
-									addErr(fmt.Sprintf(`3 Expected type of "%s" got %s instead for field "%s" %s`, sdlFld.Type.IsType(), riv.IsType(), sdlFld.Name, qry.Name.AtPosition()))
-								}
-								addErr(fmt.Sprintf(`Expected Object type got scalar  %s`, qry.Name.AtPosition()), abort)
-								//p.abort = true
-								return
-							}
-							break
-						}
-
-					default:
-						addErr(fmt.Sprintf(`Resolver response returned something other than name:value pairs. %s`, qry.Name.AtPosition()), abort)
-						//p.abort = true
-						return
-					}
-
-				} else {
-					//  --- BBB ----
-					//
-					// Resolver exists for field object
-					//
-					// if we have response data find the associated response field. First time through response will be nil as no resolver has been called.
-					//
-					var (
-						resp sdl.InputValueProvider
-						//	mismatchTypes bool
-						respType sdl.TypeFlag_
-						argFound bool
-					)
-					fmt.Println("In Resolver section . .....")
-					//
-					// First time through responseItems will be nil as no resolver has yet to be called.
-					//  On subsequent recursive calls it will contain response data from the last resolve call (the one  about to be executed below).
-					//  The objective will be to match the current query/root field with the associated field in the response data. If the response field's type does not
-					//  match the root field then try matching the reponse data against any arguments associated with the query field. If it matches then use the response data
-					//  as input when executing the resolver.
-					//
-					// find response using Name. List_ can only ever be field data.
-					//
-					if responseItems != nil {
-						switch respItem := responseItems.(type) {
-						case sdl.ObjectVals:
-							// { field: value, field: value ... } type ObjectVals []*ArgumentT   type ArgumentT struct { Name_, Value *InputValue_}   type InputValue { InputValueProvider, Loc *Loc_
-							//
-							// find response field matching current /query field name
-							//
-							for _, response := range respItem {
-								// match response field against root field and  grab the associated response field data.
-								//fmt.Println("Searching.. ", response.Name, sdlFld.Name)
-								if response.Name.EqualString(sdlFld.Name_.String()) { // name
-									resp = response.Value.InputValueProvider
-									break
-								}
-							}
-						}
-						if resp == nil {
-							addErr(fmt.Sprintf("XX No corresponding field found from response "), abort)
-							//p.abort = true
-							return
-						}
-						//
-						//	*** found response field
-						//  so we now have circumstance where the query field has a resolver but we also have response data for this field.
-						//  under this circumstance the reponse data must feed into the resolver via the "resp" argument. //TODO use input type rather than resp - maybe
-						//
-						switch y := resp.(type) {
-
-						case sdl.List_:
-							respType = y[0].InputValueProvider.IsType()
-
-						case sdl.ObjectVals:
-							// {field: value, field: value ... }, essentially an object to match againts an ast.Object_ (the fieldSet)
-							//TODO - complete implementation for ObjectVals
-							for _, v := range y {
-								fmt.Println("embedded type for ObjectVals: ", v.Name_, v.Value.InputValueProvider.IsType())
-							}
-
-						default:
-							// scalar types, Int, Float, String, EnumValues - as sdlFld.Type is an Object (see above), scalars should not appear here.
-							addErr(fmt.Sprintf(`Expect object type for response field "%s", got scalar type field %s`, qry.Name, qry.Name.AtPosition()), abort)
-							//p.abort = true
-							return
-
-						}
-						//
-						// assign response field data to "resp" argument
-						//
-						fmt.Println("find resp argument and substitute response data..")
-						for _, arg := range sdlFld.ArgumentDefs {
-							//fmt.Println(" match arguments: ", arg.Name, respType, arg.Type.IsType(), arg.Type.IsType2(), resp.IsType())
-							if arg.Type.IsType() == respType && arg.Type.IsType2() == resp.IsType() && arg.Name.EqualString("resp") {
-								//	fmt.Println("matched.....")
-								// append a "resp" argument to the query Arguments
-								// does resp exist in query arguments for current field
-								var respArg *sdl.ArgumentT
-								for _, qarg := range qry.Arguments { // TODO check how this gets populated with resp argument from  definiton
-									if qarg.Name_.EqualString("resp") {
-										respArg = qarg
-									}
-								}
-								iv := sdl.InputValue_{InputValueProvider: resp}
-								if respArg != nil {
-									respArg.Value = &iv
-								} else {
-									argT := sdl.ArgumentT{Value: &iv}
-									argT.AssignName("resp", nil, nil)
-									qry.Arguments = append(qry.Arguments, &argT)
-								}
-								argFound = true
-								break
-							}
-						}
-						if !argFound {
-							addErr(fmt.Sprintf(`Response data does not match required type "%s" or any resp argument in query field "%s"`, sdlFld.Type.TypeName(), qry.Name), abort)
-							//p.abort = true
-
-							return
-						}
-					}
-					//}
-					//
-					// response data maybe nil (first time through) or supplied from recursive call via func argument
-					//
-					if resp == nil {
-						resp = responseItems
-					}
-					//
-					//  expand field arguments and directives
-					//
-					//response := qry.Resolver(resp, qry.Arguments)
-					var ctxMsg string = `Resolver for "%s" successfully returned but`
-					ctx, cancel := context.WithTimeout(context.Background(), ResolverTimeoutMS*time.Millisecond)
-					defer cancel()
-					//
-					// verify all arguments are defined and values assigned. Add arguments if necessary
-					//
-					//fmt.Printf("sdlFld: %#v\n", sdlFld)
-					//fmt.Println("len(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
-					if qry.ExpandArguments(sdlFld, &p.perror) {
-						return
-					}
-					// for _, v := range qry.Arguments {
-					// 	fmt.Printf("argument: %s %#v\n", v.Name, v.Value)
-					// }
-					//
-					// EXECUTE RESOLVER - using current response data (nil for the first time) and any arguments associated with field
-					//
-					fmt.Println("EXECUTE RESOLVER - using current response data (nil for the first time) and any arguments associated with field")
-
-					rch := qry.Resolver(ctx, resp, qry.Arguments) // qry.Arguments -> sdl.ObjectVals as both share common def []*ArgumentT
-					//
-					// blocking wait
-					//
-					select {
-					case <-ctx.Done():
-						ctxMsg = `Resolver for "%s" timed out and consequently`
-					case response = <-rch:
-					}
-
-					fmt.Printf(`>>>>>>>  response: %s`, response)
-					fmt.Println()
-					//
-					if len(response) == 0 {
-						fldNm := qry.Name
-						if qry.Alias.Exists() {
-							fldNm = qry.Alias
-						}
-
-						addErr(fmt.Sprintf(ctxMsg+` produced no content, %s\n`, fldNm, qry.Name.AtPosition()), abort)
-						//p.abort = true
-
-						return
-					}
-					p.Lock()
-					errCnt := len(p.perror)
-					p.Unlock()
-					//
-					// generate AST from response JSON { name: value name: value ... }
-					//
-					l := lex.New(response)
-					p2 := pse.New(l)
-					respItems := p2.ParseResponse() // similar to sdl.parseArguments. Populates responseItems with parsed values from response.
-					if respItems == nil {
-						addErr(fmt.Sprintf(`Empty response from resolver for "%s" %s`, sdlFld.Name, qry.Name.AtPosition()))
-					}
-					if len(p2.Getperror()) > 0 {
-						// error in parsing stmt from db - this should not happen as only valid stmts are saved.
-						p.perror = append(p.perror, p2.Getperror()...)
-						fmt.Println("^^^^^ Response parse error: ", p2.Getperror())
-					}
-					fmt.Printf("finished ParseResponse: %T %s\n", respItems, respItems) // finished ParseResponse: ast.ObjectVals {data:[{name:"Jack Smith" age:[[53
-					//fmt.Println("** RootFld Type ", sdlFld.Type, sdlFld.Type.IsType2().String())           // [Post!] List
-					// fmt.Println("*** RootFld Type.IsType().String() ", sdlFld.Name, sdlTypeAST.TypeName()) // Object posts Post
-					// fmt.Printf("*** RootFld Type.Depth %s %T %#v, %d \n", sdlFld.Name_, sdlFld.Type.AST, sdlFld, sdlFld.Type.Depth)
-
-					//
-					//
-					// validate response against type defined in schema statemen
-					// respname_ := sdl.Name_{Name: sdl.NameValue_("response"), Loc: nil}
-					// iv := sdl.InputValue_{InputValueProvider: respItems, Loc: nil}
-					// iv.CheckInputValueType(sdlFld.Type, respname_, &p.perror)
-					// if errCnt != len(p.perror) {...
+				// find element in response that matches current query field. RespItem is a InputValue_ type
+				//
+				// response will always be "FieldName:value" pairs e.g. { data: [ { } { } ], where value may be a List_ or another ObjectVal or a scalar
+				// as a result the first (top entry) will always be an ObjectVals type
+				if _, ok := responseItems.(sdl.ObjectVals); !ok {
+					addErr(fmt.Sprintf(`Resolver response returned something other than name:value pairs. %s`, qry.Name.AtPosition()), abort)
+					return
+				}
+				respVal, err := p.resolveField(qry, fieldPath, responseItems, resolveDefault)
+				if err != nil {
+					addErr(fmt.Sprintf(`%s, %s`, err, qry.Name.AtPosition()), abort)
+					return
+				}
+				if respVal != nil {
 					//
-					// process each reqponse item and generate output based on query fields in operational statement
+					//  found query fields matching response field
 					//
-					// respItems - InputValueProvider						respItems = nil
 					writeout(pathRoot, out, fieldName)
 					writeout(pathRoot, out, ":", noNewLine)
-					//
-					// response type is either specified in the response data {reponseType:responseData} e.g. {Person:[...]}
-					//  or is defined from GQL query statement. A value of {data:[...]} means unknown and is replaced with current GQL query type.
-					// Cannot see point of using response data to define type other than as a check. wrong. need it to support interface types
-					// where the concrete type is defined in the response data.
-					//
-					if x, ok := respItems.(sdl.ObjectVals); ok {
-
-						if x[0].Name.String() != "data" {
-							responseType = x[0].Name.String()
-						} else {
-							// based on current field object in GQL query
-							responseType = sdlTypeAST.TypeName().String()
-						}
-						responseItems = x[0].Value.InputValueProvider
-					} else {
-						addErr(fmt.Sprintf(`Response should be a {type:<responseData>}, where type is "data" or name of type which data repesents e.g. Person`), abort)
-						//p.abort = true
-						return
-					}
-					//
-					if _, ok := responseItems.(sdl.List_); ok {
+					if _, ok := respVal.(sdl.List_); ok {
 						if sdlFld.Type.Depth == 0 {
-							addErr(fmt.Sprintf(`Resolver returned a list, expected a single item for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+							addErr(fmt.Sprintf(`Resolver returned a list of items, expected a single item for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
 							//p.abort = true
 							return
 						}
 					} else {
 						if sdlFld.Type.Depth > 0 {
-							addErr(fmt.Sprintf(`Resolver returned a single value, expected a list of values for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+							addErr(fmt.Sprintf(`Resolver returned a single value, expected a list for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
 							//p.abort = true
 							return
 						}
 					}
+					switch riv := respVal.(type) {
 
-					switch resp := responseItems.(type) {
 					case sdl.List_:
 						//TODO include nullable check
-						// Type check of list members will be performed in the following executeStmt checks.
-						//fmt.Println("sdlTypeAST: ", sdlTypeAST.TypeName())
-						// fmt.Println("qry.SS . ", len(qry.SelectionSet))
-						// fmt.Println("fieldPath: ", fieldPath)
-						// //TODO include nullable check
-						// fmt.Println("after resolver call - List ", resp)
+						//fmt.Println("+++++ sdlFld.Type.IsType2(), riv.IsType() = ", sdlFld.Type.IsType2(), riv.IsType())
 						if sdlFld.Type.Depth == 0 {
 							addErr(fmt.Sprintf(`Resolver returned a list, expected a single item for "%s" %s`, sdlFld.Name, qry.Name.AtPosition()))
 						}
-						//
-						// take response data (List element by List element) and match against GQL attributes of query and writeout result.
-						//
-						var f func(y sdl.List_, d uint8)
+
+						var f func(y sdl.List_, d uint8, path []interface{}, out *strings.Builder) bool
 						// f will output sdl.List_ for any level of nesting
-						// d is the depth of the listing
-						f = func(y sdl.List_, d uint8) {
+						// d is the nesting depth of List_, path the response path of y. f reports whether y is null.
+						f = func(y sdl.List_, d uint8, path []interface{}, out *strings.Builder) bool {
 
 							for i := 0; i < len(y); i++ {
 								if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
-									writeout(fieldPath, out, "[ ", noNewLine)
-									d++ // nesting depth of List_
-									if d > sdlFld.Type.Depth {
+									if d+1 > sdlFld.Type.Depth {
 										addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
 									}
-									f(x, d)
+									var list strings.Builder
+									if f(x, d+1, append(path[:len(path):len(path)], i), &list) {
+										if nonNull(sdlFld.Type, d) {
+											return true
+										}
+										writeout(fieldPath, out, "null", noNewLine)
+										continue
+									}
+									writeout(fieldPath, out, "[ ", noNewLine)
+									out.WriteString(list.String())
 									writeout(fieldPath, out, "] ", noNewLine)
-									d--
 								} else {
 									if d < sdlFld.Type.Depth {
-										addErr(fmt.Sprintf(`Expect a nesting level of %d from resolver, got a depth of %d for the List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
+										addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
 									}
 									// optimise by performing loop here rather than use outer for loop
 									for i := 0; i < len(y); i++ {
-
-										writeout(fieldPath, out, "{")
-
-										p.executeStmt_(qry.SelectionSet, fieldPath, responseType, y[i].InputValueProvider, out)
-
-										writeout(fieldPath, out, "}")
+										if p.writeObject(sset, fieldPath, append(path[:len(path):len(path)], i), responseType, y[i].InputValueProvider, out) && nonNull(sdlFld.Type, d) {
+											return true
+										}
 									}
 									break
 								}
 							}
+							return false
 						}
-						writeout(fieldPath, out, "[ ", noNewLine)
-						f(resp, 1)
-						writeout(fieldPath, out, " ]", noNewLine)
-
-						fmt.Println("after func f, List data = ", resp)
 
-					case sdl.ObjectVals: // type ArgumentS []*ArgumentT  -  represents object with fields
-						if sdlFld.Type.Depth > 0 {
-							addErr(fmt.Sprintf("Resolver returned name value pairs (Object Values), expected a %s \n", sdlFld.Type.IsType2().String()), abort)
-							//	p.abort = true
-							return
+						var list strings.Builder
+						if f(riv, 1, fieldRespPath, &list) {
+							if nonNull(sdlFld.Type, 0) {
+								return true
+							}
+							writeout(fieldPath, out, "null", noNewLine)
+						} else {
+							writeout(fieldPath, out, "[ ", noNewLine)
+							out.WriteString(list.String())
+							writeout(fieldPath, out, "] ", noNewLine)
 						}
-						fmt.Println("Response is a single object")
-						writeout(fieldPath, out, "{", noNewLine)
 
-						p.executeStmt_(qry.SelectionSet, fieldPath, responseType, responseItems, out)
+					case sdl.ObjectVals:
+						//
+						if sdlFld.Type.Depth != 0 {
+							addErr(fmt.Sprintf(`Expected List of values for "%s", resolver response returned single value %s`, sdlFld.Name, qry.Name.AtPosition()))
+						}
+						//TODO include nullable check
+						if sdlFld.Type.IsType() != riv.IsType() {
+							addErr(fmt.Sprintf(`2 Expected type of "%s" got %s instead for field "%s" %s`, sdlFld.Type.IsType(), riv.IsType(), sdlFld.Name, qry.Name.AtPosition()))
+						}
+						if p.writeObject(sset, fieldPath, fieldRespPath, responseType, riv, out, noNewLine) && nonNull(sdlFld.Type, 0) {
+							return true
+						}
 
-						writeout(fieldPath, out, "}", noNewLine)
 					default:
-						//TODO implement scalar code
-						fmt.Printf(" responseItems NOT EITHER %T\n", responseItems)
-					}
-					p.Lock()
-					if len(p.perror) > errCnt {
-						p.abort = true
-						p.Unlock()
+						//
+						if sdlFld.Type.Depth != 0 {
+							addErr(fmt.Sprintf(`Expected List of values for "%s" , resolver response returned single value instead %s`, sdlFld.Name, qry.Name.AtPosition()))
+						}
+						//TODO include nullable check
+						if sdlFld.Type.IsType() != riv.IsType() {
+							addErr(fmt.Sprintf(`3 Expected type of "%s" got %s instead for field "%s" %s`, sdlFld.Type.IsType(), riv.IsType(), sdlFld.Name, qry.Name.AtPosition()))
+						}
+						addErr(fmt.Sprintf(`Expected Object type got scalar  %s`, qry.Name.AtPosition()), abort)
+						//p.abort = true
 						return
 					}
-					p.Unlock()
 				}
 
-			// case ?
-			// error if not Object, not Union, not Interface
-
-			default:
-				//  --- CCC ----
+			} else {
+				//  --- BBB ----
 				//
-				// scalar or List of scalar. Write out its value and return.
+				// Resolver exists for field object
 				//
-				fieldPath = pathRoot + "/" + qry.Name.String()
-				//fmt.Printf("xx  is a scalar response: %T   fieldPath . %s sdlFld %T %s\n", responseItems, fieldPath, sdlFld.Type.AST, sdlFld.Type.Name)
-				qry.Resolver = p.Resolver.GetFunc(fieldPath)
-
-				if qry.Resolver == nil {
-
-					fmt.Println("NO RESOLVER...")
-					//
-					// implicit resolver - assign response value by field name
-					//
-					if responseItems == nil {
-						addErr(`responseItems is empty at scalar resolve execution`, abort)
+				// if we have response data find the associated response field. First time through response will be nil as no resolver has been called.
+				//
+				var (
+					resp sdl.InputValueProvider
+					//	mismatchTypes bool
+					respType sdl.TypeFlag_
+					argFound bool
+				)
+				//
+				// First time through responseItems will be nil as no resolver has yet to be called.
+				//  On subsequent recursive calls it will contain response data from the last resolve call (the one  about to be executed below).
+				//  The objective will be to match the current query/root field with the associated field in the response data. If the response field's type does not
+				//  match the root field then try matching the reponse data against any arguments associated with the query field. If it matches then use the response data
+				//  as input when executing the resolver.
+				//
+				// find response using Name. List_ can only ever be field data.
+				//
+				if responseItems != nil {
+					switch respItem := responseItems.(type) {
+					case sdl.ObjectVals:
+						// { field: value, field: value ... } type ObjectVals []*ArgumentT   type ArgumentT struct { Name_, Value *InputValue_}   type InputValue { InputValueProvider, Loc *Loc_
+						//
+						// find response field matching current /query field name
+						//
+						for _, response := range respItem {
+							// match response field against root field and  grab the associated response field data.
+							//fmt.Println("Searching.. ", response.Name, sdlFld.Name)
+							if response.Name.EqualString(sdlFld.Name_.String()) { // name
+								resp = response.Value.InputValueProvider
+								break
+							}
+						}
+					}
+					if resp == nil {
+						addErr(fmt.Sprintf("XX No corresponding field found from response "), abort)
 						//p.abort = true
 						return
 					}
-					writeout(pathRoot, out, fieldName)
-					writeout(pathRoot, out, ":", noNewLine)
 					//
-					// match response field for given qry field ( field have been matched already, so we know the type of the qry field)
+					//	*** found response field
+					//  so we now have circumstance where the query field has a resolver but we also have response data for this field.
+					//  under this circumstance the reponse data must feed into the resolver via the "resp" argument. //TODO use input type rather than resp - maybe
 					//
-					var resp sdl.InputValueProvider
+					switch y := resp.(type) {
 
-					switch r := responseItems.(type) {
+					case sdl.List_:
+						respType = y[0].InputValueProvider.IsType()
 
 					case sdl.ObjectVals:
-						//  { name:InputValue_ name:InputValue_ ... }
-						for _, response := range r {
-							//
-							// find response field by matching name against  field and  grab the associated response field data.
-							//
-							if response.Name.EqualString(sdlFld.Name_.String()) { // name
-								resp = response.Value.InputValueProvider
-								break
-							}
+						// {field: value, field: value ... }, essentially an object to match againts an ast.Object_ (the fieldSet)
+						//TODO - complete implementation for ObjectVals
+						for _, v := range y {
+							p.log.Log(logger.Debug, "embedded object in response", "field", v.Name_, "type", v.Value.InputValueProvider.IsType())
 						}
 
-					case *sdl.InputValue_:
-						// typical response from Union member field. response name field matches inline fragment "on" clause.
-						// {name : value } -> {dataType : []*ArgumentT } -> {dataType: { name:InputValue_ name: InputValue_} } ->
-						switch x := r.InputValueProvider.(type) {
-						case sdl.ObjectVals:
-							// loop thru matching response field with  field
-							for _, v := range x {
-								if v.Name.EqualString(qry.Name.String()) { //sdlFld.Name_.String()) { // name qry.Name.String()
-									resp = v.Value.InputValueProvider
-									break
+					default:
+						// scalar types, Int, Float, String, EnumValues - as sdlFld.Type is an Object (see above), scalars should not appear here.
+						addErr(fmt.Sprintf(`Expect object type for response field "%s", got scalar type field %s`, qry.Name, qry.Name.AtPosition()), abort)
+						//p.abort = true
+						return
+
+					}
+					//
+					// assign response field data to "resp" argument
+					//
+					for _, arg := range sdlFld.ArgumentDefs {
+						//fmt.Println(" match arguments: ", arg.Name, respType, arg.Type.IsType(), arg.Type.IsType2(), resp.IsType())
+						if arg.Type.IsType() == respType && arg.Type.IsType2() == resp.IsType() && arg.Name.EqualString("resp") {
+							//	fmt.Println("matched.....")
+							// append a "resp" argument to the query Arguments
+							// does resp exist in query arguments for current field
+							var respArg *sdl.ArgumentT
+							for _, qarg := range qry.Arguments { // TODO check how this gets populated with resp argument from  definiton
+								if qarg.Name_.EqualString("resp") {
+									respArg = qarg
 								}
 							}
+							iv := sdl.InputValue_{InputValueProvider: resp}
+							if respArg != nil {
+								respArg.Value = &iv
+							} else {
+								argT := sdl.ArgumentT{Value: &iv}
+								argT.AssignName("resp", nil, nil)
+								qry.Arguments = append(qry.Arguments, &argT)
+							}
+							argFound = true
+							break
 						}
 					}
-					if resp == nil {
-						addErr(fmt.Sprintf(`No corresponding  field found from response field, "%s"`, fieldName), abort)
+					if !argFound {
+						addErr(fmt.Sprintf(`Response data does not match required type "%s" or any resp argument in query field "%s"`, sdlFld.Type.TypeName(), qry.Name), abort)
 						//p.abort = true
+
 						return
 					}
-					//
-					// got matching response field, now output the response
-					//
-					if _, ok := resp.(sdl.List_); ok {
-						if sdlFld.Type.Depth == 0 {
-							addErr(fmt.Sprintf(`Resolver returned a list, expected single value for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
-							//p.abort = true
-							return
+				}
+				//}
+				//
+				// response data maybe nil (first time through) or supplied from recursive call via func argument
+				//
+				if resp == nil {
+					resp = responseItems
+				}
+				//
+				//  expand field arguments and directives
+				//
+				//response := qry.Resolver(resp, qry.Arguments)
+				//
+				// verify all arguments are defined and values assigned. Add arguments if necessary
+				//
+				//fmt.Printf("sdlFld: %#v\n", sdlFld)
+				//fmt.Println("len(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
+				if expandArguments(qry, sdlFld) {
+					return
+				}
+				// for _, v := range qry.Arguments {
+				// 	fmt.Printf("argument: %s %#v\n", v.Name, v.Value)
+				// }
+				//
+				// EXECUTE RESOLVER - using current response data (nil for the first time) and any arguments associated with field
+				//
+				p.perrorMx.Lock()
+				errCnt := len(p.perror)
+				p.perrorMx.Unlock()
+				respItems, err := p.resolveField(qry, fieldPath, responseItems, p.callResolver(qry, resp))
+				if err != nil {
+					addErr(fmt.Sprintf(`%s, %s`, err, qry.Name.AtPosition()), abort)
+					return
+				}
+				//fmt.Println("** RootFld Type ", sdlFld.Type, sdlFld.Type.IsType2().String())           // [Post!] List
+				// fmt.Println("*** RootFld Type.IsType().String() ", sdlFld.Name, sdlTypeAST.TypeName()) // Object posts Post
+				// fmt.Printf("*** RootFld Type.Depth %s %T %#v, %d \n", sdlFld.Name_, sdlFld.Type.AST, sdlFld, sdlFld.Type.Depth)
+
+				//
+				//
+				// validate response against type defined in schema statemen
+				// respname_ := sdl.Name_{Name: sdl.NameValue_("response"), Loc: nil}
+				// iv := sdl.InputValue_{InputValueProvider: respItems, Loc: nil}
+				// iv.CheckInputValueType(sdlFld.Type, respname_, &p.perror)
+				// if errCnt != len(p.perror) {...
+				//
+				// process each reqponse item and generate output based on query fields in operational statement
+				//
+				// respItems - InputValueProvider						respItems = nil
+				writeout(pathRoot, out, fieldName)
+				writeout(pathRoot, out, ":", noNewLine)
+				//
+				// response type is either specified in the response data {reponseType:responseData} e.g. {Person:[...]}
+				//  or is defined from GQL query statement. A value of {data:[...]} means unknown and is replaced with current GQL query type.
+				// Cannot see point of using response data to define type other than as a check. wrong. need it to support interface types
+				// where the concrete type is defined in the response data.
+				//
+				if x, ok := respItems.(sdl.ObjectVals); ok {
+
+					if x[0].Name.String() != "data" {
+						responseType = x[0].Name.String()
+					} else {
+						// based on current field object in GQL query
+						responseType = sdlTypeAST.TypeName().String()
+					}
+					responseItems = x[0].Value.InputValueProvider
+				} else {
+					addErr(fmt.Sprintf(`Response should be a {type:<responseData>}, where type is "data" or name of type which data repesents e.g. Person`), abort)
+					//p.abort = true
+					return
+				}
+				//
+				if _, ok := responseItems.(sdl.List_); ok {
+					if sdlFld.Type.Depth == 0 {
+						addErr(fmt.Sprintf(`Resolver returned a list, expected a single item for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+						//p.abort = true
+						return
+					}
+				} else {
+					if sdlFld.Type.Depth > 0 {
+						addErr(fmt.Sprintf(`Resolver returned a single value, expected a list of values for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+						//p.abort = true
+						return
+					}
+				}
+
+				switch resp := responseItems.(type) {
+				case sdl.List_:
+					//TODO include nullable check
+					// Type check of list members will be performed in the following executeStmt checks.
+					//fmt.Println("sdlTypeAST: ", sdlTypeAST.TypeName())
+					// fmt.Println("qry.SS . ", len(qry.SelectionSet))
+					// fmt.Println("fieldPath: ", fieldPath)
+					// //TODO include nullable check
+					// fmt.Println("after resolver call - List ", resp)
+					if sdlFld.Type.Depth == 0 {
+						addErr(fmt.Sprintf(`Resolver returned a list, expected a single item for "%s" %s`, sdlFld.Name, qry.Name.AtPosition()))
+					}
+					//
+					// take response data (List element by List element) and match against GQL attributes of query and writeout result.
+					//
+					var f func(y sdl.List_, d uint8, path []interface{}, out *strings.Builder) bool
+					// f will output sdl.List_ for any level of nesting
+					// d is the depth of the listing, path the response path of y. f reports whether y is null.
+					f = func(y sdl.List_, d uint8, path []interface{}, out *strings.Builder) bool {
+
+						for i := 0; i < len(y); i++ {
+							if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
+								if d+1 > sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
+								}
+								var list strings.Builder
+								if f(x, d+1, append(path[:len(path):len(path)], i), &list) {
+									if nonNull(sdlFld.Type, d) {
+										return true
+									}
+									writeout(fieldPath, out, "null", noNewLine)
+									continue
+								}
+								writeout(fieldPath, out, "[ ", noNewLine)
+								out.WriteString(list.String())
+								writeout(fieldPath, out, "] ", noNewLine)
+							} else {
+								if d < sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Expect a nesting level of %d from resolver, got a depth of %d for the List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
+								}
+								// optimise by performing loop here rather than use outer for loop
+								for i := 0; i < len(y); i++ {
+									if p.writeObject(sset, fieldPath, append(path[:len(path):len(path)], i), responseType, y[i].InputValueProvider, out) && nonNull(sdlFld.Type, d) {
+										return true
+									}
+								}
+								break
+							}
 						}
+						return false
+					}
+					var list strings.Builder
+					if f(resp, 1, fieldRespPath, &list) {
+						if nonNull(sdlFld.Type, 0) {
+							return true
+						}
+						writeout(fieldPath, out, "null", noNewLine)
+					} else {
+						writeout(fieldPath, out, "[ ", noNewLine)
+						out.WriteString(list.String())
+						writeout(fieldPath, out, " ]", noNewLine)
+					}
+
+				case sdl.ObjectVals: // type ArgumentS []*ArgumentT  -  represents object with fields
+					if sdlFld.Type.Depth > 0 {
+						addErr(fmt.Sprintf("Resolver returned name value pairs (Object Values), expected a %s \n", sdlFld.Type.IsType2().String()), abort)
+						//	p.abort = true
+						return
+					}
+					if p.writeObject(sset, fieldPath, fieldRespPath, responseType, responseItems, out, noNewLine) && nonNull(sdlFld.Type, 0) {
+						return true
+					}
+				default:
+					//TODO implement scalar code
+					p.log.Log(logger.Warn, "response type not supported", "path", fieldPath, "type", fmt.Sprintf("%T", responseItems))
+				}
+				p.perrorMx.Lock()
+				if len(p.perror) > errCnt {
+					p.abort = true
+					p.perrorMx.Unlock()
+					return
+				}
+				p.perrorMx.Unlock()
+			}
+
+		// case ?
+		// error if not Object, not Union, not Interface
+
+		default:
+			//  --- CCC ----
+			//
+			// scalar or List of scalar. Write out its value and return.
+			//
+			fieldPath = pathRoot + "/" + qry.Name.String()
+			//fmt.Printf("xx  is a scalar response: %T   fieldPath . %s sdlFld %T %s\n", responseItems, fieldPath, sdlFld.Type.AST, sdlFld.Type.Name)
+			qry.Resolver = p.Resolver.GetFunc(fieldPath)
+
+			if qry.Resolver == nil {
+
+				//
+				// implicit resolver - assign response value by field name
+				//
+				if responseItems == nil {
+					addErr(`responseItems is empty at scalar resolve execution`, abort)
+					//p.abort = true
+					return
+				}
+				writeout(pathRoot, out, fieldName)
+				writeout(pathRoot, out, ":", noNewLine)
+				//
+				// match response field for given qry field ( field have been matched already, so we know the type of the qry field)
+				//
+				resp, err := p.resolveField(qry, fieldPath, responseItems, resolveDefault)
+				if err != nil {
+					addErr(fmt.Sprintf(`%s, %s`, err, qry.Name.AtPosition()), abort)
+					return
+				}
+				if resp == nil {
+					addErr(fmt.Sprintf(`No corresponding  field found from response field, "%s"`, fieldName), abort)
+					//p.abort = true
+					return
+				}
+				//
+				// got matching response field, now output the response
+				//
+				if _, ok := resp.(sdl.List_); ok {
+					if sdlFld.Type.Depth == 0 {
+						addErr(fmt.Sprintf(`Resolver returned a list, expected single value for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+						//p.abort = true
+						return
 					}
-					if _, ok := resp.(sdl.List_); !ok {
-						if sdlFld.Type.Depth > 0 {
-							addErr(fmt.Sprintf(`Resolver returned single item, expected a List for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
-							//p.abort = true
-							return
-						}
+				}
+				if _, ok := resp.(sdl.List_); !ok {
+					if sdlFld.Type.Depth > 0 {
+						addErr(fmt.Sprintf(`Resolver returned single item, expected a List for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()), abort)
+						//p.abort = true
+						return
 					}
-					// resp is InputValue_ type
-					switch riv := resp.(type) { // value
-
-					case sdl.ObjectVals:
-
-						fmt.Println("in ObjectVals: ", riv)
-
-					case sdl.List_:
-						//type List_ []*InputValue_ . type InputValue_ struct {InputValueProvider	,Loc  *Loc_}
-						// [                                                             ]     sdl.List_        depth=3
-						//  [                          ] [              ] [             ]       sdl.List_        depth=2
-						//   [1 2 3] [1 2 3 12] [23 32]   [23 23] [2 5]    [3 5] [3 6 6]         sdl.List_        depth=1
-						//    1 2 3                                                               int values       depth=0
-						// string() len(l)  2 *ast.InputValue_  ast.List_ 0
-						// string() len(l)  3 *ast.InputValue_  ast.Int_ 0
-						// string() len(l)  3 *ast.InputValue_  ast.Int_ 1
-						// string() len(l)  3 *ast.InputValue_  ast.Int_ 2
-						// string() len(l)  2 *ast.InputValue_  ast.List_ 1
-						// string() len(l)  4 *ast.InputValue_  ast.Int_ 0
-						// string() len(l)  4 *ast.InputValue_  ast.Int_ 1
-						// string() len(l)  4 *ast.InputValue_  ast.Int_ 2
-						// string() len(l)  4 *ast.InputValue_  ast.Int_ 3
-						// [2]x
-						// x[0] -> s[3] -> scalar
-						// x[1] -> s[4] -> scalar
-						//  type should be List_
-
-						if sdlFld.Type.Depth == 0 {
-							addErr(fmt.Sprintf(`Expected a single value for "%s" , response returned a List  %s`, sdlFld.Name, qry.Name.AtPosition()))
-						}
-
-						var f func(y sdl.List_, d uint8)
-						// f will output sdl.List_ for any level of nesting
-						// d is the nesting depth of List_
-						f = func(y sdl.List_, d uint8) {
-
-							for i := 0; i < len(y); i++ {
-								if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
-									writeout(fieldPath, out, "[ ", noNewLine)
-									d++ // nesting depth of List_
-									if d > sdlFld.Type.Depth {
-										addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
-									}
-									f(x, d)
-									writeout(fieldPath, out, "] ", noNewLine)
-									d--
-								} else {
-									if d < sdlFld.Type.Depth {
-										addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
-									}
-									// optimise by performing loop here rather than use outer for loop
-									for i := 0; i < len(y); i++ {
-										// for scalar only Type.Name contains the scalar type name i.e. Int, Float, Boolean etc. For ENUM and Scalar types, Name does not identify type, use BaseType, passing in the type AST.
-										//fmt.Println("y[i].IsType().String(), sdlFld.Type.Name.String() -=-", y[i].IsType().String(), sdlFld.Type.Name.String())
-										if y[i].IsType().String() != sdlFld.Type.Name.String() {
-											if !(y[i].IsType().String() == "Enum" && sdl.BaseType(sdlFld.Type.AST) == "E") {
-												if _, ok := y[i].InputValueProvider.(sdl.Null_); !ok {
-													addErr(fmt.Sprintf(`XX Expected "%s" got %s for "%s" %s`, sdlFld.Type.Name_.String(), y[i].IsType(), qry.Name, qry.Name.AtPosition()))
-												} else {
-													var bit byte = 1
-													bit &= sdlFld.Type.Constraint >> uint(d)
-													if bit == 1 {
-														addErr(fmt.Sprintf(`Expected non-null got null for "%s" %s`, qry.Name, qry.Name.AtPosition()))
-													}
+				}
+				// resp is InputValue_ type
+				switch riv := resp.(type) { // value
+
+				case sdl.ObjectVals:
+
+				case sdl.List_:
+					//type List_ []*InputValue_ . type InputValue_ struct {InputValueProvider	,Loc  *Loc_}
+					// [                                                             ]     sdl.List_        depth=3
+					//  [                          ] [              ] [             ]       sdl.List_        depth=2
+					//   [1 2 3] [1 2 3 12] [23 32]   [23 23] [2 5]    [3 5] [3 6 6]         sdl.List_        depth=1
+					//    1 2 3                                                               int values       depth=0
+					// string() len(l)  2 *ast.InputValue_  ast.List_ 0
+					// string() len(l)  3 *ast.InputValue_  ast.Int_ 0
+					// string() len(l)  3 *ast.InputValue_  ast.Int_ 1
+					// string() len(l)  3 *ast.InputValue_  ast.Int_ 2
+					// string() len(l)  2 *ast.InputValue_  ast.List_ 1
+					// string() len(l)  4 *ast.InputValue_  ast.Int_ 0
+					// string() len(l)  4 *ast.InputValue_  ast.Int_ 1
+					// string() len(l)  4 *ast.InputValue_  ast.Int_ 2
+					// string() len(l)  4 *ast.InputValue_  ast.Int_ 3
+					// [2]x
+					// x[0] -> s[3] -> scalar
+					// x[1] -> s[4] -> scalar
+					//  type should be List_
+
+					if sdlFld.Type.Depth == 0 {
+						addErr(fmt.Sprintf(`Expected a single value for "%s" , response returned a List  %s`, sdlFld.Name, qry.Name.AtPosition()))
+					}
+
+					var f func(y sdl.List_, d uint8)
+					// f will output sdl.List_ for any level of nesting
+					// d is the nesting depth of List_
+					f = func(y sdl.List_, d uint8) {
+
+						for i := 0; i < len(y); i++ {
+							if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
+								writeout(fieldPath, out, "[ ", noNewLine)
+								d++ // nesting depth of List_
+								if d > sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
+								}
+								f(x, d)
+								writeout(fieldPath, out, "] ", noNewLine)
+								d--
+							} else {
+								if d < sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
+								}
+								// optimise by performing loop here rather than use outer for loop
+								for i := 0; i < len(y); i++ {
+									// for scalar only Type.Name contains the scalar type name i.e. Int, Float, Boolean etc. For ENUM and Scalar types, Name does not identify type, use BaseType, passing in the type AST.
+									//fmt.Println("y[i].IsType().String(), sdlFld.Type.Name.String() -=-", y[i].IsType().String(), sdlFld.Type.Name.String())
+									if y[i].IsType().String() != sdlFld.Type.Name.String() {
+										if !(y[i].IsType().String() == "Enum" && sdl.BaseType(sdlFld.Type.AST) == "E") {
+											if _, ok := y[i].InputValueProvider.(sdl.Null_); !ok {
+												addErr(fmt.Sprintf(`XX Expected "%s" got %s for "%s" %s`, sdlFld.Type.Name_.String(), y[i].IsType(), qry.Name, qry.Name.AtPosition()))
+											} else {
+												var bit byte = 1
+												bit &= sdlFld.Type.Constraint >> uint(d)
+												if bit == 1 {
+													addErr(fmt.Sprintf(`Expected non-null got null for "%s" %s`, qry.Name, qry.Name.AtPosition()))
 												}
 											}
 										}
-										//fmt.Println("======================================= writeout scalar ================================", y[i].String())
-										writeout(fieldPath, out, y[i].String(), noNewLine)
 									}
-									break
+									//fmt.Println("======================================= writeout scalar ================================", y[i].String())
+									writeout(fieldPath, out, y[i].String(), noNewLine)
 								}
+								break
 							}
 						}
+					}
 
-						writeout(fieldPath, out, "[ ", noNewLine)
-						f(riv, 1)
-						writeout(fieldPath, out, "] ", noNewLine)
-
-					case sdl.String_:
-						// TODO: remove this case - using "null" to represent null value in response string
-						var bit byte = 1
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							return
-						}
-						bit &= sdlFld.Type.Constraint
-						if bit == 1 && riv.String() == "null" {
-							addErr(fmt.Sprintf(`Cannot be null for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
-						}
-						if !(sdlFld.Type.Name_.String() == sdl.STRING.String() || sdlFld.Type.Name_.String() == sdl.RAWSTRING.String()) {
-							addErr(fmt.Sprintf(`3 Expected String got %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
-						}
-						s_ := string(`"` + riv.String() + `"`)
-						writeout(fieldPath, out, s_, noNewLine)
-
-					case sdl.RawString_:
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							return
-						}
-						s_ := string(`"""` + riv.String() + `"""`)
-						writeout(fieldPath, out, s_, noNewLine)
-
-					case sdl.Null_:
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`5 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							return
-						}
-						var bit byte = 1
-						bit &= sdlFld.Type.Constraint
-						if bit == 1 {
-							addErr(fmt.Sprintf(`Value cannot be null %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
-						}
-
-					case sdl.Int_:
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							return
-						}
-						s_ := riv.String()
-						writeout(fieldPath, out, s_, noNewLine)
+					writeout(fieldPath, out, "[ ", noNewLine)
+					f(riv, 1)
+					writeout(fieldPath, out, "] ", noNewLine)
 
-					case sdl.Float_:
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							return
-						}
-						s_ := riv.String()
-						writeout(fieldPath, out, s_, noNewLine)
+				case sdl.String_:
+					// TODO: remove this case - using "null" to represent null value in response string
+					var bit byte = 1
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
+						return
+					}
+					bit &= sdlFld.Type.Constraint
+					if bit == 1 && riv.String() == "null" {
+						addErr(fmt.Sprintf(`Cannot be null for %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
+					}
+					if !(sdlFld.Type.Name_.String() == sdl.STRING.String() || sdlFld.Type.Name_.String() == sdl.RAWSTRING.String()) {
+						addErr(fmt.Sprintf(`3 Expected String got %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
+					}
+					s_ := string(`"` + riv.String() + `"`)
+					writeout(fieldPath, out, s_, noNewLine)
 
-					default:
-						if sdlFld.Type.Name.String() != riv.IsType().String() {
-							addErr(fmt.Sprintf(`6 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-						} else {
-							s_ := resp.String()
-							addErr(fmt.Sprintf(`6 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
-							writeout(fieldPath, out, s_, noNewLine)
-						}
+				case sdl.RawString_:
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
+						return
 					}
-					//
-					// only field for an Input type must be present if not-null constraint enabled. Normal query field may or may not be present
-					//
-					// if !foundResp {
-					// 	fmt.Println("NOT FOUND ", qry.Name)
-					// 	var bit byte = '1'
-					// 	bit &= sdlFld.Type.Constraint
-					// 	fmt.Printf("No field value bit: %08b Depth: %d \n", bit, sdlFld.Type.Depth)
-					// 	if bit == 1 {
-					// 		p.addErr(fmt.Sprintf(`Expected %s Value, resolver returned no result for "%s" %s`, sdlFld.Type.Name_.String(), qry.Name, qry.Name.AtPosition()))
-					// 	}
-					// }
+					s_ := string(`"""` + riv.String() + `"""`)
+					writeout(fieldPath, out, s_, noNewLine)
 
-				} else {
-					//  --- DDD ---
-					//
-					// scalar Resolver exists
-					//
-					// find relevant response field associated with current query field
-					//
-					var resp sdl.InputValueProvider
-					switch y := responseItems.(type) {
-					case sdl.ObjectVals:
-						fmt.Println("123 responseItems : ", y.String())
-						for _, response := range y {
-							// find response field by matching name against  field and grab the associated response field data.
-							if response.Name.EqualString(sdlFld.Name_.String()) { // name
-								resp = response.Value.InputValueProvider
-							}
-						}
+				case sdl.Null_:
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`5 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
+						return
 					}
-					if resp == nil {
-						addErr(fmt.Sprintf("yy No corresponding field found from response "), abort)
-						//p.abort = true
+					var bit byte = 1
+					bit &= sdlFld.Type.Constraint
+					if bit == 1 {
+						addErr(fmt.Sprintf(`Value cannot be null %s %s`, sdlFld.Type.Name_.String(), qry.Name.AtPosition()))
+					}
+
+				case sdl.Int_:
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
 						return
 					}
-					//
-					// verify all arguments are defined and values assigned. Add arguments if necessary
-					//
-					// fmt.Printf("sdlFld: %#v\n", sdlFld)
-					// fmt.Println("len(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
-					if qry.ExpandArguments(sdlFld, &p.perror) {
+					s_ := riv.String()
+					writeout(fieldPath, out, s_, noNewLine)
+
+				case sdl.Float_:
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`4 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
 						return
 					}
-					//fmt.Println("xxlen(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
-					// for _, v := range qry.Arguments {
-					// 	fmt.Printf("argument: %s %#v\n", v.Name, v.Value)
-					// }
-					// create timeout context and pass to Resolver
-					ctx, cancel := context.WithTimeout(context.Background(), ResolverTimeoutMS*time.Millisecond)
-					defer cancel()
-					// execute resolver using response data for field
-					rch := qry.Resolver(ctx, resp, qry.Arguments)
-					// blocking wait
-					select {
-					case <-ctx.Done():
-					case response = <-rch:
-					}
-
-					fmt.Println("Response >>>>> ", response)
-					// generate  AST from response JSON { name: value name: value ... }
-					l := lex.New(response)
-					p2 := pse.New(l)
-					// scope of responseItems restricted to Section --- DDD --- to hide argument responseItems
-					responseItems := p2.ParseResponse() // similar to sdl.parseArguments
-					fmt.Println("Response >>>>> ", responseItems)
-					if len(p2.Getperror()) > 0 {
-						// error in parsing stmt from db - this should not happen as only valid stmts are saved.
-						p.perror = append(p.perror, p2.Getperror()...)
+					s_ := riv.String()
+					writeout(fieldPath, out, s_, noNewLine)
+
+				default:
+					if sdlFld.Type.Name.String() != riv.IsType().String() {
+						addErr(fmt.Sprintf(`6 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
+					} else {
+						s_ := resp.String()
+						addErr(fmt.Sprintf(`6 Expected "%s" got %s %s`, sdlFld.Type.Name_.String(), riv.IsType().String(), qry.Name.AtPosition()))
+						writeout(fieldPath, out, s_, noNewLine)
 					}
-					writeout(pathRoot, out, fieldName)
-					writeout(pathRoot, out, ":", noNewLine)
-					//fmt.Printf("+++ sdlTypeAST %T %s\n", sdlFld, sdlFld.Name_)
-					//
-					switch r := responseItems.(type) {
-					case sdl.ObjectVals:
-						// developer wraps resolver output in { name: value } where name is query field name e.g. age
-						for _, response := range r {
-							//
-							// find response field by matching name against sdl field name and  grab the associated response field data.
-							//
-							fmt.Println("XXXX", response)
-							if response.Name.EqualString(sdlFld.Name_.String()) { // name
-								resp = response.Value.InputValueProvider
-								//fmt.Println("Found ", sdlFld.Name_.String())
-								break
-							}
+				}
+				//
+				// only field for an Input type must be present if not-null constraint enabled. Normal query field may or may not be present
+				//
+				// if !foundResp {
+				// 	fmt.Println("NOT FOUND ", qry.Name)
+				// 	var bit byte = '1'
+				// 	bit &= sdlFld.Type.Constraint
+				// 	fmt.Printf("No field value bit: %08b Depth: %d \n", bit, sdlFld.Type.Depth)
+				// 	if bit == 1 {
+				// 		p.addErr(fmt.Sprintf(`Expected %s Value, resolver returned no result for "%s" %s`, sdlFld.Type.Name_.String(), qry.Name, qry.Name.AtPosition()))
+				// 	}
+				// }
+
+			} else {
+				//  --- DDD ---
+				//
+				// scalar Resolver exists
+				//
+				// find relevant response field associated with current query field
+				//
+				var resp sdl.InputValueProvider
+				switch y := responseItems.(type) {
+				case sdl.ObjectVals:
+					for _, response := range y {
+						// find response field by matching name against  field and grab the associated response field data.
+						if response.Name.EqualString(sdlFld.Name_.String()) { // name
+							resp = response.Value.InputValueProvider
 						}
-					default:
-						// developer does not wrap resolver output
-						resp = r
 					}
-					switch riv := resp.(type) {
-
-					case sdl.List_: // type List_ []*InputValue_ - respresents many sdl.ObjectVals
+				}
+				if resp == nil {
+					addErr(fmt.Sprintf("yy No corresponding field found from response "), abort)
+					//p.abort = true
+					return
+				}
+				//
+				// verify all arguments are defined and values assigned. Add arguments if necessary
+				//
+				// fmt.Printf("sdlFld: %#v\n", sdlFld)
+				// fmt.Println("len(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
+				if expandArguments(qry, sdlFld) {
+					return
+				}
+				//fmt.Println("xxlen(sdlFld.ArgumentDefs) ", len(sdlFld.ArgumentDefs))
+				// for _, v := range qry.Arguments {
+				// 	fmt.Printf("argument: %s %#v\n", v.Name, v.Value)
+				// }
+				// execute resolver using response data for field
+				// scope of responseItems restricted to Section --- DDD --- to hide argument responseItems
+				responseItems, err := p.resolveField(qry, fieldPath, responseItems, p.callResolver(qry, resp))
+				if err != nil {
+					addErr(fmt.Sprintf(`%s, %s`, err, qry.Name.AtPosition()), abort)
+					return
+				}
+				writeout(pathRoot, out, fieldName)
+				writeout(pathRoot, out, ":", noNewLine)
+				//fmt.Printf("+++ sdlTypeAST %T %s\n", sdlFld, sdlFld.Name_)
+				//
+				switch r := responseItems.(type) {
+				case sdl.ObjectVals:
+					// developer wraps resolver output in { name: value } where name is query field name e.g. age
+					for _, response := range r {
 						//
-						// does response match expected type
+						// find response field by matching name against sdl field name and  grab the associated response field data.
 						//
-						var f func(y sdl.List_, d uint8)
-						// f will output sdl.List_ for any level of nesting
-						// d is the nesting depth of List_
-						f = func(y sdl.List_, d uint8) {
+						if response.Name.EqualString(sdlFld.Name_.String()) { // name
+							resp = response.Value.InputValueProvider
+							//fmt.Println("Found ", sdlFld.Name_.String())
+							break
+						}
+					}
+				default:
+					// developer does not wrap resolver output
+					resp = r
+				}
+				switch riv := resp.(type) {
 
-							for i := 0; i < len(y); i++ {
-								if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
-									writeout(fieldPath, out, "[ ", noNewLine)
-									d++ // nesting depth of List_
-									if d > sdlFld.Type.Depth {
-										addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
-									}
-									f(x, d)
-									writeout(fieldPath, out, "] ", noNewLine)
-									d--
-								} else {
-									if d < sdlFld.Type.Depth {
-										addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
-									}
-									// optimise by performing loop here rather than use outer for loop
-									for i := 0; i < len(y); i++ {
-										// for scalar only Type.Name contains the scalar type name i.e. Int, Float, Boolean etc
-										if y[i].IsType().String() != sdlFld.Type.Name.String() {
-											if _, ok := y[i].InputValueProvider.(sdl.Null_); !ok {
-												addErr(fmt.Sprintf(`66 Expected "%s" got %s for "%s" %s`, sdlFld.Type.Name_.String(), y[i].IsType(), qry.Name, qry.Name.AtPosition()))
-											} else {
-												var bit byte = 1
-												bit &= sdlFld.Type.Constraint >> uint(d)
-												if bit == 1 {
-													addErr(fmt.Sprintf(`Expected non-null got null for "%s" %s`, qry.Name, qry.Name.AtPosition()))
-												}
+				case sdl.List_: // type List_ []*InputValue_ - respresents many sdl.ObjectVals
+					//
+					// does response match expected type
+					//
+					var f func(y sdl.List_, d uint8)
+					// f will output sdl.List_ for any level of nesting
+					// d is the nesting depth of List_
+					f = func(y sdl.List_, d uint8) {
+
+						for i := 0; i < len(y); i++ {
+							if x, ok := y[i].InputValueProvider.(sdl.List_); ok {
+								writeout(fieldPath, out, "[ ", noNewLine)
+								d++ // nesting depth of List_
+								if d > sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Exceeds nesting of List type for "%s" %s`, qry.Name, qry.Name.AtPosition()))
+								}
+								f(x, d)
+								writeout(fieldPath, out, "] ", noNewLine)
+								d--
+							} else {
+								if d < sdlFld.Type.Depth {
+									addErr(fmt.Sprintf(`Expect a nesting level of %d, got %d, for scalar values in List for "%s" %s`, sdlFld.Type.Depth, d, qry.Name, qry.Name.AtPosition()))
+								}
+								// optimise by performing loop here rather than use outer for loop
+								for i := 0; i < len(y); i++ {
+									// for scalar only Type.Name contains the scalar type name i.e. Int, Float, Boolean etc
+									if y[i].IsType().String() != sdlFld.Type.Name.String() {
+										if _, ok := y[i].InputValueProvider.(sdl.Null_); !ok {
+											addErr(fmt.Sprintf(`66 Expected "%s" got %s for "%s" %s`, sdlFld.Type.Name_.String(), y[i].IsType(), qry.Name, qry.Name.AtPosition()))
+										} else {
+											var bit byte = 1
+											bit &= sdlFld.Type.Constraint >> uint(d)
+											if bit == 1 {
+												addErr(fmt.Sprintf(`Expected non-null got null for "%s" %s`, qry.Name, qry.Name.AtPosition()))
 											}
 										}
-										writeout(fieldPath, out, y[i].String(), noNewLine)
 									}
-									break
+									writeout(fieldPath, out, y[i].String(), noNewLine)
 								}
+								break
 							}
 						}
-						writeout(fieldPath, out, "[ ", noNewLine)
-						f(riv, 1)
-						writeout(fieldPath, out, "] ", noNewLine)
-					//
-					// sdl.ObjectVals - represents Objects which is not appropriate in the scalar section
-					//
-					default:
-						fmt.Printf(" responseItems NOT EITHER %T\n", responseItems)
 					}
+					writeout(fieldPath, out, "[ ", noNewLine)
+					f(riv, 1)
+					writeout(fieldPath, out, "] ", noNewLine)
+				//
+				// sdl.ObjectVals - represents Objects which is not appropriate in the scalar section
+				//
+				default:
+					p.log.Log(logger.Warn, "response type not supported", "path", fieldPath, "type", fmt.Sprintf("%T", responseItems))
 				}
 			}
-			// for object fields recursively call its fields, otherwise return
-			// if sdlTypeAST != nil && len(x.SelectionSet) != 0 {
-			// 	// new  object
-			// 	p.executeStmt_(sdlTypeAST, x.SelectionSet, pathRoot+"/"+string(sdlTypeAST.TypeName()), responseItems, out)
+		}
+		// for object fields recursively call its fields, otherwise return
+		// if sdlTypeAST != nil && len(x.SelectionSet) != 0 {
+		// 	// new  object
+		// 	p.executeStmt_(sdlTypeAST, x.SelectionSet, pathRoot+"/"+string(sdlTypeAST.TypeName()), responseItems, out)
 
-			// }
+		// }
+	}
+	return false
+}
+
+// collectedField is a field of a selection set after its fragments are expanded. Fields of the same response key
+// are resolved once: the first field selected, with the sub-selections of all of them. pathRoot is the path of the
+// field's enclosing type, which an inline fragment extends with its type condition.
+type collectedField struct {
+	field        *ast.Field
+	pathRoot     string
+	selectionSet []ast.SelectionSetProvider
+}
+
+// collectFields implements the CollectFields algorithm of the spec. It expands the fragment spreads and inline fragments
+// of gqlsset that apply to the response type, and groups the fields by response key in the order each key first appears.
+// It returns false when execution must abort.
+func (p *Parser) collectFields(gqlsset []ast.SelectionSetProvider, pathRoot string, responseType string, fields *[]*collectedField, byKey map[string]*collectedField) bool {
+
+	const (
+		abort bool = true
+	)
+
+	addErr := func(s string, abort ...bool) {
+		p.perrorMx.Lock()
+		p.addErr(s, abort...)
+		p.perrorMx.Unlock()
+	}
+
+	for _, qryFld := range gqlsset {
+
+		switch qry := qryFld.(type) {
+
+		case *ast.Field:
+			key := responseName(qry)
+			if cf, ok := byKey[key]; ok {
+				// merge sub-selections - full slice expression so the statement's selection set is never appended to
+				cf.selectionSet = append(cf.selectionSet[:len(cf.selectionSet):len(cf.selectionSet)], qry.SelectionSet...)
+				continue
+			}
+			cf := &collectedField{field: qry, pathRoot: pathRoot, selectionSet: qry.SelectionSet}
+			byKey[key] = cf
+			*fields = append(*fields, cf)
 
 		case *ast.FragmentSpread:
 
 			// FragmentSpread
 			// ...FragmentName	Directives-opt
 			//  TODO - check that type of enclosing object of field matches the fragment typeCond
-			fmt.Println("FRAGMENT SPREAD.........")
 			var (
 				displayFrg bool = true
 				// dir        sdl.Directives_
@@ -2234,15 +2496,13 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 					for _, arg := range d.Arguments {
 						if arg.Name.String() != "if" {
 							addErr(fmt.Sprintf(`Expected argument name of "if", got %s %s`, arg.Name, arg.AtPosition()))
-							return
+							return false
 						}
 						// parse wil have populated argument value with variable value.
 						if argv, ok := arg.Value.InputValueProvider.(sdl.Bool_); ok {
 							displayFrg = bool(argv)
 						}
 					}
-				} else {
-					fmt.Println("no @include directive")
 				}
 			}
 			if !displayFrg {
@@ -2251,24 +2511,24 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 			//
 			//  validate response against field type
 			//
-			respType, err := p.tyCache.FetchAST(sdl.NameValue_(responseType))
+			respType, err := p.tyCache.FetchAST(sdl.NameValue_(responseType), p.log)
 			if err != nil {
 				addErr(err.Error())
 			}
 			if respType == nil {
 				addErr(fmt.Sprintf(`Response type "%s" not defined in Graphql repository"`, responseType))
-				return
+				return false
 			}
 			respObj, ok := respType.(*sdl.Object_)
 			if !ok {
 				addErr(fmt.Sprintf(`Response type "%s" is not a Graphql Object`, responseType), abort)
 				// p.abort = true // shared variable needs syncing
-				return
+				return false
 			}
 			//
 			// confirm response type matches fragment type (expected type - expType )
 			//
-			expType, err := p.tyCache.FetchAST(qry.FragStmt.TypeCond.Name)
+			expType, err := p.tyCache.FetchAST(qry.FragStmt.TypeCond.Name, p.log)
 			if err != nil {
 				addErr(err.Error())
 			}
@@ -2297,20 +2557,20 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 							for _, arg := range d.Arguments {
 								if arg.Name.String() != "if" {
 									addErr(fmt.Sprintf(`Expected argument name of "if", got %s %s`, arg.Name, arg.AtPosition()))
-									return
+									return false
 								}
 								// parse wil have populated argument value with variable value.
 								if argv, ok := arg.Value.InputValueProvider.(sdl.Bool_); ok {
 									displayFrg = bool(argv)
 								}
 							}
-						} else {
-							fmt.Println("no @include directive")
 						}
 					}
 					if displayFrg {
 						//
-						p.executeStmt_(qry.FragStmt.SelectionSet, pathRoot, responseType, responseItems, out)
+						if !p.collectFields(qry.FragStmt.SelectionSet, pathRoot, responseType, fields, byKey) {
+							return false
+						}
 					}
 
 				case *sdl.Interface_:
@@ -2325,9 +2585,8 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 						}
 					}
 					if !implements {
-						addErr(fmt.Sprintf(`Response type "%s" does not implement interface "%s"`, responseType, x.Name_), abort)
-						//p.abort = true
-						return
+						// the fragment does not apply to the response type (DoesFragmentTypeApply), e.g. a member of a union
+						continue
 					}
 					for _, d := range qry.FragStmt.Directives {
 						//... @include(if: $expandedInfo) {
@@ -2335,23 +2594,56 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 							for _, arg := range d.Arguments {
 								if arg.Name.String() != "if" {
 									addErr(fmt.Sprintf(`Expected argument name of "if", got %s %s`, arg.Name, arg.AtPosition()))
-									return
+									return false
 								}
 								// parse wil have populated argument value with variable value.
 								if argv, ok := arg.Value.InputValueProvider.(sdl.Bool_); ok {
 									displayFrg = bool(argv)
 								}
 							}
-						} else {
-							fmt.Println("no @include directive")
 						}
 					}
 					if displayFrg {
-						p.executeStmt_(qry.FragStmt.SelectionSet, pathRoot, responseType, responseItems, out)
+						if !p.collectFields(qry.FragStmt.SelectionSet, pathRoot, responseType, fields, byKey) {
+							return false
+						}
 					}
 
 				case *sdl.Union_:
-					//TODO implement
+					//
+					// expected type is a Union. So is the response type a member of the union.
+					//
+					var member bool
+					for _, v := range x.NameS {
+						if v.EqualString(responseType) {
+							member = true
+							break
+						}
+					}
+					if !member {
+						// the fragment does not apply to the response type
+						continue
+					}
+					for _, d := range qry.FragStmt.Directives {
+						//... @include(if: $expandedInfo) {
+						if d.Name_.String() == "@include" {
+							for _, arg := range d.Arguments {
+								if arg.Name.String() != "if" {
+									addErr(fmt.Sprintf(`Expected argument name of "if", got %s %s`, arg.Name, arg.AtPosition()))
+									return false
+								}
+								// parse wil have populated argument value with variable value.
+								if argv, ok := arg.Value.InputValueProvider.(sdl.Bool_); ok {
+									displayFrg = bool(argv)
+								}
+							}
+						}
+					}
+					if displayFrg {
+						if !p.collectFields(qry.FragStmt.SelectionSet, pathRoot, responseType, fields, byKey) {
+							return false
+						}
+					}
 				}
 			}
 
@@ -2364,6 +2656,7 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 			// sdl.Directives_
 			// SelectionSet []SelectionSetProvider // { only fields and ... fragments. Nil when no TypeCond and adopts selectionSet of enclosing context.
 			//
+			pathRoot := pathRoot
 			last := func(a string) string {
 				n := strings.Split(a, "/")
 				return n[len(n)-1]
@@ -2375,18 +2668,18 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 			if !qry.TypeCond.Exists() && len(qry.Directives) == 0 {
 				addErr("type condition does not exist. Shoud have been popoulated during parsing.", abort)
 				//p.abort = true
-				return
+				return false
 			}
 			//
 			// populate type condition's AST if not already assigned
 			//
 			if qry.TypeCondAST == nil && len(qry.Directives) == 0 {
 				var err error
-				qry.TypeCondAST, err = p.tyCache.FetchAST(qry.TypeCond.Name)
+				qry.TypeCondAST, err = p.tyCache.FetchAST(qry.TypeCond.Name, p.log)
 				if err != nil {
 					addErr(err.Error(), abort)
 					//p.abort = true
-					return
+					return false
 				}
 			}
 			//
@@ -2400,21 +2693,21 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 			//
 			// check response data {reponseType:responseItems} against the field type (determined by type condition for inline frags - see prevous stmt)
 			//
-			respAST, err := p.tyCache.FetchAST(sdl.NameValue_(responseType)) //TODO - eleminate this cache lookup by passing the responseAST rather than reesponseType
+			respAST, err := p.tyCache.FetchAST(sdl.NameValue_(responseType), p.log) //TODO - eleminate this cache lookup by passing the responseAST rather than reesponseType
 			if err != nil {
 				addErr(err.Error())
 			}
 			if respAST == nil {
 				addErr(fmt.Sprintf(`Response type "%s" not defined in Graphql respository"`, responseType))
 				//p.abort = true
-				return
+				return false
 			}
 			//
 			respObj, ok := respAST.(*sdl.Object_)
 			if !ok {
 				addErr(fmt.Sprintf(`Response type "%s" is not a SDL Object`, responseType), abort)
 				//p.abort = true
-				return
+				return false
 			}
 			//
 			// anaylze directives
@@ -2427,7 +2720,7 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 					for _, arg := range v.Arguments {
 						if arg.Name.String() != "if" {
 							addErr(fmt.Sprintf(`include directive error, expected argument name of "if", got %s %s`, arg.Name, arg.AtPosition()))
-							return
+							return false
 						}
 						// parse wil have populated argument value with variable value.
 						// TODO - variable value should be sourced during execution not parsing. Fix.
@@ -2470,7 +2763,7 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 						}
 					}
 					if !found {
-						addErr(fmt.Sprintf(`Response type "%s" does not match any member in the Union type %s`, responseType, qry.TypeCondAST.TypeName()))
+						// not a member of the union - ignore this field and proceed to next
 						continue
 					}
 
@@ -2485,9 +2778,12 @@ func (p *Parser) executeStmt_(gqlsset []ast.SelectionSetProvider, pathRoot strin
 				}
 			}
 
-			p.executeStmt_(qry.SelectionSet, pathRoot, responseType, responseItems, out)
+			if !p.collectFields(qry.SelectionSet, pathRoot, responseType, fields, byKey) {
+				return false
+			}
 		}
 	}
+	return true
 }
 
 // ====================================================================================
@@ -2515,8 +2811,8 @@ func (p *Parser) parseOperationStmt(op string) ast.GQLStmtProvider {
 	//   For short stmts, hidden name is provided: __NONAME/<id>
 	//
 	f = func(nw sdl.NameValue_) {
-		if _, ok := OperationStmts[nw]; !ok {
-			OperationStmts[nw] = stmt
+		if _, ok := p.operationStmts[nw]; !ok {
+			p.operationStmts[nw] = stmt
 			stmt.Name.Name = nw
 			return
 		} else {
@@ -2546,12 +2842,14 @@ func (p *Parser) parseFragmentStmt(op string) ast.GQLStmtProvider {
 	)
 	p.nextToken()               // read over Fragment keyword
 	stmt := &ast.FragmentStmt{} // TODO: alternative to Stmt field could simply use check len(Name) to determine if Stmt or inline
+	p.inFragment = true
+	defer func() { p.inFragment = false }()
 
 	_ = p.parseName(stmt).parseFragmentStmtTypeCondition(stmt).parseDirectives(stmt, opt).parseSelectionSet(stmt)
 
 	f = func(nw sdl.NameValue_) {
-		if _, ok := FragmentStmts[nw]; !ok {
-			FragmentStmts[nw] = stmt
+		if _, ok := p.fragmentStmts[nw]; !ok {
+			p.fragmentStmts[nw] = stmt
 			stmt.Name.Name = nw
 			return
 		} else {
@@ -2592,7 +2890,6 @@ func (p *Parser) parseFragmentSpread() ast.SelectionSetProvider {
 func (p *Parser) parseInlineFragment(f ast.HasSelectionSetProvider) ast.SelectionSetProvider {
 
 	frag := &ast.InlineFragment{} //{Parent: f}
-	fmt.Println(p.curToken)
 	frag.Name_ = sdl.Name_{Loc: p.Loc()}
 
 	p.nextToken() // read over ...
@@ -2643,7 +2940,6 @@ func (p *Parser) parseAlias(f *ast.Field, optional ...bool) *Parser {
 			p.addErr("Expect an alias")
 		}
 	}
-	fmt.Println("parseAlias: ", f.Alias)
 	return p
 }
 
@@ -2667,7 +2963,7 @@ func (p *Parser) parseName(f ast.NameI, optional ...bool) *Parser { // type f *a
 		case token.LPAREN, token.ATSIGN, token.LBRACE: // token.IDENT:
 		default:
 			p.printToken("parseName abort...")
-			p.abort = true
+			p.addErr(fmt.Sprintf(`Unexpected %s "%s"`, p.curToken.Type, p.curToken.Literal), true)
 		}
 	}
 
@@ -2682,7 +2978,7 @@ func (p *Parser) parseInlineTypeCondition(f ast.FragmentDef, optional ...bool) *
 	}
 	if p.curToken.Type != token.ON {
 		if len(optional) == 0 {
-			p.addErr(fmt.Sprintf("Expecting ON keyword got %s %s", p.curToken.Type, p.curToken.Literal))
+			p.addErr(fmt.Sprintf("Expecting ON keyword got %s %s", p.curToken.Type, p.curToken.Literal), true)
 		}
 		return p
 	}
@@ -2693,7 +2989,7 @@ func (p *Parser) parseInlineTypeCondition(f ast.FragmentDef, optional ...bool) *
 			f.AssignTypeCond(p.curToken.Literal, p.Loc(), &p.perror)
 			p.nextToken() // read over IDENT
 		} else {
-			p.addErr(fmt.Sprintf("Expecting an identifer for type condition got %s %s", p.curToken.Type, p.curToken.Literal))
+			p.addErr(fmt.Sprintf("Expecting an identifer for type condition got %s %s", p.curToken.Type, p.curToken.Literal), true)
 		}
 	}
 	//
@@ -2706,7 +3002,7 @@ func (p *Parser) parseFragmentStmtTypeCondition(f ast.FragmentDef, optional ...b
 	}
 	if p.curToken.Type != token.ON {
 		if len(optional) == 0 {
-			p.addErr(fmt.Sprintf("Expecting ON keyword got %s %s", p.curToken.Type, p.curToken.Literal))
+			p.addErr(fmt.Sprintf("Expecting ON keyword got %s %s", p.curToken.Type, p.curToken.Literal), true)
 		}
 		return p
 	}
@@ -2717,7 +3013,7 @@ func (p *Parser) parseFragmentStmtTypeCondition(f ast.FragmentDef, optional ...b
 			f.AssignTypeCond(p.curToken.Literal, p.Loc(), &p.perror)
 			p.nextToken() // read over IDENT
 		} else {
-			p.addErr(fmt.Sprintf("Expecting an identifer for type condition got %s %s", p.curToken.Type, p.curToken.Literal))
+			p.addErr(fmt.Sprintf("Expecting an identifer for type condition got %s %s", p.curToken.Type, p.curToken.Literal), true)
 		}
 	}
 	//
@@ -2931,11 +3227,17 @@ func (p *Parser) parseSelectionSet(f ast.HasSelectionSetProvider, optional ...bo
 	// read an LBRACE therefore have a selectionset to process. Each node/item in the SS must be either a Field, FragmentSpread, InlineFragment
 	for p.nextToken(); p.curToken.Type != token.RBRACE; {
 
+		pos := p.curToken.Loc // the lexer reuses its tokens, so compare positions
 		node := parseSSet()
 
 		if p.hasError() {
 			break
 		}
+		if p.curToken.Loc == pos {
+			// no progress - abandon the statement rather than repeat the error, ParseDocument resumes at the next statement
+			p.abort = true
+			break
+		}
 
 		f.AppendSelectionSet(node) // append each selection set current receiver.
 
@@ -2966,17 +3268,19 @@ func (p *Parser) parseVariables(st ast.OperationDef, optional ...bool) *Parser {
 	}
 
 	parseVariable := func(v *ast.VariableDef) bool {
-		// first time curToken = (
-		if p.peekToken.Type != token.DOLLAR && p.peekToken.Type != token.COMMENT {
-			p.abort = true
-			if p.peekToken.Type == token.IDENT {
-				p.addErr(fmt.Sprintf(`Missing "$" %s`, p.peekToken.AtPosition()))
+		// first time curToken = (, thereafter the $ of the next variable
+		if p.curToken.Type != token.DOLLAR {
+			if p.peekToken.Type != token.DOLLAR && p.peekToken.Type != token.COMMENT {
+				p.abort = true
+				if p.peekToken.Type == token.IDENT {
+					p.addErr(fmt.Sprintf(`Missing "$" %s`, p.peekToken.AtPosition()))
+					return false
+				}
+				p.addErr(fmt.Sprintf(`Expected "$" got %q %s`, p.peekToken.Literal, p.peekToken.AtPosition()))
 				return false
 			}
-			p.addErr(fmt.Sprintf(`Expected "$" got %q %s`, p.peekToken.Literal, p.peekToken.AtPosition()))
-			return false
+			p.nextToken()
 		}
-		p.nextToken()
 		p.nextToken() // read over $
 		if !(p.curToken.Type == token.IDENT && p.peekToken.Type == token.COLON) {
 			p.addErr(fmt.Sprintf(`Expected an identifer got an "%s" value "%s"`, p.curToken.Type, p.curToken.Literal))
@@ -2996,6 +3300,9 @@ func (p *Parser) parseVariables(st ast.OperationDef, optional ...bool) *Parser {
 			p.nextToken() // read over ASSIGN
 			v.DefaultVal = p.parseInputValue_()
 		}
+		if val, ok := p.variables[v.Name.String()]; ok && !p.syntax {
+			v.Value = p.variableValue(val, v.Type, v.Name_.Loc)
+		}
 		return true
 	}
 
@@ -3073,13 +3380,17 @@ func (p *Parser) parseInputValue_() *sdl.InputValue_ {
 		// change category of token to VALUE as previous token was $ - otherwise this step would not be executed.
 		p.curToken.Cat = token.VALUE
 		if p.curToken.Type == token.IDENT {
+			if p.syntax {
+				return &sdl.InputValue_{InputValueProvider: ast.Variable_(p.curToken.Literal), Loc: p.Loc()}
+			}
 			// get variable value....
-			if val, ok := p.getVarValue(p.curToken.Literal); !ok {
+			val, ok := p.getVarValue(p.curToken.Literal)
+			if !ok && !p.inFragment {
 				p.addErr(fmt.Sprintf("Variable, %s not defined ", p.curToken.Literal))
 				return nil
-			} else {
-				return val
 			}
+			// fragment variables are bound to the operation's variables during validation
+			return p.useVariable(p.curToken.Literal, val)
 		} else {
 			p.addErr(fmt.Sprintf("Expected Variable Name Identifer got %s", p.curToken.Type))
 			return nil
@@ -3096,6 +3407,9 @@ func (p *Parser) parseInputValue_() *sdl.InputValue_ {
 		// }
 		// edge case: empty, []
 		if p.curToken.Type == token.RBRACKET {
+			if p.syntax {
+				return &sdl.InputValue_{InputValueProvider: sdl.List_{}, Loc: p.Loc()}
+			}
 			p.nextToken() // ]
 			var null sdl.Null_ = true
 			iv := sdl.InputValue_{InputValueProvider: null, Loc: p.Loc()}
@@ -3115,6 +3429,7 @@ func (p *Parser) parseInputValue_() *sdl.InputValue_ {
 	//
 	case token.LBRACE:
 		//  { name:value name:value ... }
+		loc := p.Loc()             // errors for the object as a whole are reported at the {
 		p.nextToken()              // read over {
 		var ObjList sdl.ObjectVals // []*ArgumentT {Name_,Value *InputValue_}
 		for p.curToken.Type != token.RBRACE {
@@ -3124,7 +3439,7 @@ func (p *Parser) parseInputValue_() *sdl.InputValue_ {
 				return &sdl.InputValue_{}
 			}
 		}
-		iv := sdl.InputValue_{InputValueProvider: ObjList, Loc: p.Loc()}
+		iv := sdl.InputValue_{InputValueProvider: ObjList, Loc: loc}
 		return &iv
 	//
 	//  Standard Scalar types
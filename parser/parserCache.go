@@ -1,9 +1,8 @@
 package parser
 
 import (
-	"fmt"
-
 	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/logger"
 )
 
 // for fragment  & operatinal statments
@@ -15,16 +14,18 @@ type entry struct {
 type Cache_ struct {
 	//	sync.Mutex
 	cache map[string]*entry
+	log   logger.Logger
 }
 
-func newCache() *Cache_ {
-	return &Cache_{cache: make(map[string]*entry)}
+func newCache(log logger.Logger) *Cache_ {
+	return &Cache_{cache: make(map[string]*entry), log: log}
 }
 
 // addEntry is not concurrent safe. Intended for a single thread operation.
 func (t *Cache_) addEntry(name ast.StmtName_, stmt ast.GQLStmtProvider) {
 	e := &entry{data: stmt}
 	t.cache[string(name)] = e
+	t.log.Log(logger.Debug, "statement cached", "name", name)
 }
 
 // FetchAST - TODO: copy code from sdl.??
@@ -42,8 +43,6 @@ func (t *Cache_) fetchAST(name ast.StmtName_) ast.GQLStmtProvider {
 }
 
 func (t *Cache_) cacheClear() {
-	fmt.Println("******************************************")
-	fmt.Println("************ CLEAR CACHE *****************")
-	fmt.Println("******************************************")
+	t.log.Log(logger.Debug, "clear statement cache", "entries", len(t.cache))
 	t.cache = make(map[string]*entry)
 }
@@ -25,6 +25,15 @@ func trimWS(input string) string {
 
 }
 
+// checkUnusedVariable parses input with the variable definitions vars added after the operation's name, op,
+// and checks the only error is expectedErr, reporting a variable that is not used.
+func checkUnusedVariable(input, op, vars, expectedErr string, t *testing.T) {
+	t.Helper()
+	p := New(lexer.New(strings.Replace(input, op, op+vars, 1)))
+	_, errs := p.ParseDocument()
+	checkErrors(errs, []string{expectedErr}, t)
+}
+
 func TestParseQuery(t *testing.T) {
 	{
 		//
@@ -295,7 +304,9 @@ func TestParseNoName2(t *testing.T) {
   }
 }
 `
-	var parseErrs []string
+	parseErrs := []string{
+		`Variable "$devicePicSize" is not used at line: 1 column: 9`,
+	}
 
 	l := lexer.New(input)
 	p := New(l)
@@ -483,7 +494,9 @@ func TestParseMissingDollar3(t *testing.T) {
 }
 }
 `
-	var parseErrs []string
+	parseErrs := []string{
+		`Variable "$devicePicSize" is not used by operation "getZuckProfile" at line: 2 column: 3`,
+	}
 
 	l := lexer.New(input)
 	p := New(l)
@@ -549,6 +562,7 @@ func TestParseIllegalInName(t *testing.T) {
 }
 `
 	parseErrs := []string{
+		`Illegal character "*" at line: 2, column: 15`,
 		`Expected an identifier for a fragment or inlinefragment got ILLEGAL. at line: 2, column: 15`,
 	}
 
@@ -677,7 +691,9 @@ func TestParseStmtVariableNoDefault(t *testing.T) {
 }
 `
 
-	var parseErrs []string
+	parseErrs := []string{
+		`Variable "$devicePicSize" is not used by operation "getZuckProfile" at line: 1 column: 23`,
+	}
 
 	schema := "DefaultDoc"
 	l := lexer.New(input)
@@ -737,7 +753,7 @@ func TestParseMultiArgValue(t *testing.T) {
 		setup(input, t)
 	}
 
-	var input = `query getZuckProfile($devicePicSize: Int = 1234) {
+	var input = `query getZuckProfile {
   xyzalias: user(id: 23 name: """Ross""" ) {
     sex
     author
@@ -761,6 +777,7 @@ func TestParseMultiArgValue(t *testing.T) {
 			t.Errorf(`Unexpected document for %s. `, t.Name())
 		}
 	}
+	checkUnusedVariable(input, "query getZuckProfile", "($devicePicSize: Int = 1234)", `Variable "$devicePicSize" is not used by operation "getZuckProfile" at line: 1 column: 23`, t)
 
 	//
 	// teardown
@@ -877,7 +894,9 @@ func TestParseNullValue(t *testing.T) {
   }
 }
 `
-	var parseErrs []string
+	parseErrs := []string{
+		`Variable "$devicePicSize" is not used by operation "getZuckProfile" at line: 1 column: 23`,
+	}
 	schema := "DefaultDoc"
 	l := lexer.New(input)
 	p := New(l)
@@ -941,14 +960,14 @@ func TestParseUseOfCommas(t *testing.T) {
 	//
 	// test
 	//
-	var input = `query getZuckProfile($devicePicSize: Int = 1234) {
+	var input = `query getZuckProfile {
   xyzalias: user(id: [1 2,, 34, ,56 , ]) {
     sex
     author
   }
 }
 `
-	expectedDoc := `	query getZuckProfile ( $devicePicSize : Int = 1234) { 
+	expectedDoc := `	query getZuckProfile { 
                         xyzalias : user (id:[1 2 34 56] ) {
                                 sex
                                 author
@@ -971,6 +990,7 @@ func TestParseUseOfCommas(t *testing.T) {
 		}
 		t.Log("doc.String(): " + doc.String())
 	}
+	checkUnusedVariable(input, "query getZuckProfile", "($devicePicSize: Int = 1234)", `Variable "$devicePicSize" is not used by operation "getZuckProfile" at line: 1 column: 23`, t)
 	//
 	// teardown
 	//
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+func TestErrorRecovery(t *testing.T) {
+
+	s, errs := NewSchema(mergeSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `query A { hero { id name } }
+			        query B { hero(episode: ) { id } }
+			        query C { hero { id } }
+			        query D { hero { i*d } }`,
+			errs: []string{
+				`Expected an argument name or a right parenthesis got "{ id" at line: 2, column: 38`,
+				`Illegal character "*" at line: 4, column: 30`,
+				`Expected an identifier for a fragment or inlinefragment got ILLEGAL. at line: 4, column: 30`,
+			},
+		},
+		{ // Names are ASCII
+			input: `query { hero { i界d } }`,
+			errs: []string{
+				`Illegal character "界" at line: 1, column: 17`,
+				`Expected an identifier for a fragment or inlinefragment got ILLEGAL. at line: 1, column: 17`,
+			},
+		},
+		{ // a missing } swallows the following statement
+			input: `query A { hero { id }
+			        query B { hero { id } }`,
+			errs: []string{`Expected an identifier for a fragment or inlinefragment got QUERY. at line: 2, column: 12`},
+		},
+		{
+			input: `fragment F { id }
+			        query Q { hero { id } }
+			        query R { hero { ... } }`,
+			errs: []string{
+				`Expecting ON keyword got { { at line: 1, column: 12`,
+				`expected IDENT or ON or @ or LBRACE after spread ... at line: 3, column: 29`,
+			},
+		},
+		{
+			input: `} query A { hero { id } } query B ( { hero { id } }`,
+			errs: []string{
+				`Unexpected } "}" at line: 1, column: 1`,
+				`Expected "$" got "{" at line: 1, column: 37`,
+			},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
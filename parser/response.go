@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Response is the GraphQL response to an executed document, for encoding by encoding/json.
+// Data is omitted when the document failed before execution.
+type Response struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	Errors     Errors          `json:"errors,omitempty"`
+	Extensions json.RawMessage `json:"extensions,omitempty"`
+}
+
+// Errors of a response. A field error is encoded with its path and code, any other error by its message.
+type Errors []error
+
+func (e Errors) MarshalJSON() ([]byte, error) {
+	type message struct {
+		Message string `json:"message"`
+	}
+	msgs := make([]interface{}, len(e))
+	for i, err := range e {
+		if fe, ok := err.(*FieldError); ok {
+			msgs[i] = fe
+			continue
+		}
+		msgs[i] = message{err.Error()}
+	}
+	return json.Marshal(msgs)
+}
+
+// NewResponse returns the response to a document from the result and errors of ExecuteDocument.
+// The result, written in the syntax of a resolver response, is converted to JSON. The errors of the
+// response are errs, whether or not there is a result.
+func NewResponse(result string, errs []error) (*Response, error) {
+
+	resp := &Response{Errors: errs}
+	if len(strings.TrimSpace(result)) == 0 {
+		return resp, nil
+	}
+	values, err := resultJSON(result)
+	if err != nil {
+		return nil, err
+	}
+	//
+	// a document of several operations has a result for each. Their data members are merged.
+	//
+	var data []json.RawMessage
+	for _, v := range values {
+		var r struct {
+			Data       json.RawMessage `json:"data"`
+			Extensions json.RawMessage `json:"extensions"`
+		}
+		if err := json.Unmarshal(v, &r); err != nil {
+			return nil, fmt.Errorf("Result is not an object: %w", err)
+		}
+		if len(r.Data) > 0 {
+			data = append(data, r.Data)
+		}
+		if len(r.Extensions) > 0 {
+			resp.Extensions = r.Extensions
+		}
+	}
+	switch len(data) {
+	case 0:
+	case 1:
+		resp.Data = data[0]
+	default:
+		var merged bytes.Buffer
+		merged.WriteByte('{')
+		for _, d := range data {
+			d = bytes.TrimSpace(d)
+			if len(d) < 2 || d[0] != '{' || len(bytes.TrimSpace(d[1:len(d)-1])) == 0 {
+				continue
+			}
+			if merged.Len() > 1 {
+				merged.WriteByte(',')
+			}
+			merged.Write(d[1 : len(d)-1])
+		}
+		merged.WriteByte('}')
+		resp.Data = merged.Bytes()
+	}
+	return resp, nil
+}
+
+// resultJSON converts each value of an executed document's result to JSON. The result has the syntax of a
+// resolver response: names are unquoted, members and items are separated by white space, a null value may be
+// omitted and enum values are unquoted. The data of an operation of several fields is a list of their members.
+func resultJSON(result string) ([]json.RawMessage, error) {
+	r := &resultReader{input: result}
+	var values []json.RawMessage
+	for r.skip(); r.pos < len(r.input); r.skip() {
+		var out bytes.Buffer
+		if err := r.value(&out); err != nil {
+			return nil, err
+		}
+		values = append(values, out.Bytes())
+	}
+	return values, nil
+}
+
+type resultReader struct {
+	input string
+	pos   int
+}
+
+// skip advances past white space and commas.
+func (r *resultReader) skip() {
+	for r.pos < len(r.input) && (unicode.IsSpace(rune(r.input[r.pos])) || r.input[r.pos] == ',') {
+		r.pos++
+	}
+}
+
+func (r *resultReader) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("Result is not well formed at offset %d: %s", r.pos, fmt.Sprintf(format, a...))
+}
+
+// value writes the value at the current position as JSON.
+func (r *resultReader) value(out *bytes.Buffer) error {
+	r.skip()
+	if r.pos == len(r.input) {
+		return r.errorf("expected a value")
+	}
+	switch c := r.input[r.pos]; c {
+	case '{':
+		r.pos++
+		return r.members(out, '}')
+	case '[':
+		r.pos++
+		if r.atMember() {
+			// the data of an operation of several fields
+			return r.members(out, ']')
+		}
+		out.WriteByte('[')
+		for n := 0; ; n++ {
+			r.skip()
+			if r.pos == len(r.input) {
+				return r.errorf("expected ]")
+			}
+			if r.input[r.pos] == ']' {
+				r.pos++
+				out.WriteByte(']')
+				return nil
+			}
+			if n > 0 {
+				out.WriteByte(',')
+			}
+			if err := r.value(out); err != nil {
+				return err
+			}
+		}
+	case '"':
+		s, err := r.str()
+		if err != nil {
+			return err
+		}
+		b, _ := json.Marshal(s)
+		out.Write(b)
+		return nil
+	default:
+		w := r.word()
+		if len(w) == 0 {
+			return r.errorf("unexpected %q", c)
+		}
+		switch {
+		case w == "null" || w == "true" || w == "false" || json.Valid([]byte(w)):
+			out.WriteString(w)
+		default:
+			// enum value
+			b, _ := json.Marshal(w)
+			out.Write(b)
+		}
+		return nil
+	}
+}
+
+// members writes the members up to close as a JSON object.
+func (r *resultReader) members(out *bytes.Buffer, close byte) error {
+	out.WriteByte('{')
+	for n := 0; ; n++ {
+		r.skip()
+		if r.pos == len(r.input) {
+			return r.errorf("expected %c", close)
+		}
+		if r.input[r.pos] == close {
+			r.pos++
+			out.WriteByte('}')
+			return nil
+		}
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		r.skip()
+		if r.pos == len(r.input) || r.input[r.pos] != ':' {
+			return r.errorf(`expected : after "%s"`, name)
+		}
+		r.pos++
+		if n > 0 {
+			out.WriteByte(',')
+		}
+		b, _ := json.Marshal(name)
+		out.Write(b)
+		out.WriteByte(':')
+		// a null value is omitted
+		if r.skip(); r.pos == len(r.input) || r.input[r.pos] == close || r.atMember() {
+			out.WriteString("null")
+			continue
+		}
+		if err := r.value(out); err != nil {
+			return err
+		}
+	}
+}
+
+// atMember reports whether a name followed by a colon is at the current position.
+func (r *resultReader) atMember() bool {
+	save := r.pos
+	defer func() { r.pos = save }()
+	r.skip()
+	if _, err := r.name(); err != nil {
+		return false
+	}
+	r.skip()
+	return r.pos < len(r.input) && r.input[r.pos] == ':'
+}
+
+// name reads a member name, unquoted or quoted.
+func (r *resultReader) name() (string, error) {
+	if r.pos < len(r.input) && r.input[r.pos] == '"' {
+		return r.str()
+	}
+	if w := r.word(); len(w) > 0 {
+		return w, nil
+	}
+	return "", r.errorf("expected a name")
+}
+
+// word reads a name, number or keyword.
+func (r *resultReader) word() string {
+	start := r.pos
+	for r.pos < len(r.input) {
+		c := r.input[r.pos]
+		if !(c == '_' || c == '-' || c == '+' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			break
+		}
+		r.pos++
+	}
+	return r.input[start:r.pos]
+}
+
+// str reads a string or block string. The escape sequences of a string are decoded when they are valid JSON,
+// otherwise the string is taken as written, as resolvers' response strings are not decoded.
+func (r *resultReader) str() (string, error) {
+	if strings.HasPrefix(r.input[r.pos:], `"""`) {
+		end := strings.Index(r.input[r.pos+3:], `"""`)
+		if end < 0 {
+			return "", r.errorf("unterminated block string")
+		}
+		s := r.input[r.pos+3 : r.pos+3+end]
+		r.pos += end + 6
+		return s, nil
+	}
+	start := r.pos
+	for r.pos++; r.pos < len(r.input) && r.input[r.pos] != '"'; r.pos++ {
+		if r.input[r.pos] == '\\' {
+			r.pos++
+		}
+	}
+	if r.pos >= len(r.input) {
+		r.pos = start
+		return "", r.errorf("unterminated string")
+	}
+	r.pos++
+	var s string
+	if err := json.Unmarshal([]byte(r.input[start:r.pos]), &s); err != nil {
+		return r.input[start+1 : r.pos-1], nil
+	}
+	return s, nil
+}
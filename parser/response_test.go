@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+func TestNewResponse(t *testing.T) {
+
+	for _, v := range []struct {
+		result string
+		errs   []error
+		json   string
+	}{
+		{
+			result: "\n{\ndata: {\n hero : [ \n {\n id : \"1\"\n name : \"Lu\\u0022ke\"\n ep : JEDI\n }  ]\n}\n}",
+			json:   `{"data":{"hero":[{"id":"1","name":"Lu\"ke","ep":"JEDI"}]}}`,
+		},
+		{
+			// an operation of several fields, a null value omitted, a block string and a string with an undecoded escape
+			result: ` { data : [ hero : { name : age : -4.5E-2 ok : true } bio : """a "b" c""" path : "C:\dir"
+ ] } `,
+			json: `{"data":{"hero":{"name":null,"age":-4.5E-2,"ok":true},"bio":"a \"b\" c","path":"C:\\dir"}}`,
+		},
+		{
+			result: "{data: {hero: {books: null}},\nerrors: [{\"message\":\"denied\",\"path\":[\"hero\",\"books\"]}],\nextensions: {\"tracing\":{\"version\":1}}\n}",
+			errs:   []error{&FieldError{Message: "denied", Path: []interface{}{"hero", "books"}, Code: "FORBIDDEN"}},
+			json:   `{"data":{"hero":{"books":null}},"errors":[{"message":"denied","path":["hero","books"],"extensions":{"code":"FORBIDDEN"}}],"extensions":{"tracing":{"version":1}}}`,
+		},
+		{
+			// operations of a document are merged
+			result: `{data: {a: 1}}{data: {b: [1 2]}}`,
+			json:   `{"data":{"a":1,"b":[1,2]}}`,
+		},
+		{
+			errs: []error{errors.New(`Field "books" is not defined`)},
+			json: `{"errors":[{"message":"Field \"books\" is not defined"}]}`,
+		},
+	} {
+		resp, err := NewResponse(v.result, v.errs)
+		if err != nil {
+			t.Fatalf("%s: %s", v.result, err)
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != v.json {
+			t.Errorf("Got:      %s", b)
+			t.Errorf("Expected: %s", v.json)
+		}
+	}
+	for _, result := range []string{`{data: {hero: }`, `{data: {hero: "Luke}}`, `{data: {hero: [1 2}}`} {
+		if _, err := NewResponse(result, nil); err == nil {
+			t.Errorf("Expected error for result %s", result)
+		}
+	}
+}
+
+func TestExecuteResponse(t *testing.T) {
+
+	s, errs := NewSchema(collectSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	p := NewWithSchema(lexer.New(`query { hero(episode: JEDI) { name friends { id } } search { ... on Starship { name } } }`), s)
+	p.Resolver.Register("Query/hero", resolveFriends)
+	p.Resolver.Register("Query/search", resolveSearch)
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	resp, err := NewResponse(p.ExecuteDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(resp)
+	var got struct {
+		Data struct {
+			Hero []struct {
+				Name    string
+				Friends []struct{ ID string }
+			}
+			Search []struct{ Name string }
+		}
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("%s: %s", b, err)
+	}
+	if len(got.Data.Hero) != 1 || got.Data.Hero[0].Name != "Luke" || len(got.Data.Hero[0].Friends) != 2 || len(got.Data.Search) != 1 || got.Data.Search[0].Name != "Falcon" {
+		t.Errorf("Unexpected response %s", b)
+	}
+}
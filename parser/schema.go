@@ -0,0 +1,282 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/store"
+)
+
+// Schema is a set of SDL types parsed once into a type cache. Parsers created with NewWithSchema
+// validate and execute documents against the schema's types only, without reference to a document store.
+//
+// A schema is replaced by Update, Reload or Watch. The new types are validated before they are
+// swapped in, so a failed reload leaves the current types in place. Parsers keep the types that
+// were current when they were created, so documents in progress finish on the old schema.
+type Schema struct {
+	version  uint64 // incremented on each replacement of the type cache. First for 64-bit alignment.
+	document string
+	glob     string // source files of a schema created by LoadSchemaFiles
+
+	sync.Mutex              // serialises replacement of the type cache
+	tyCache    atomic.Value // *typeCache
+}
+
+// NewSchema parses and validates the types defined in sdlText.
+func NewSchema(sdlText string) (*Schema, []error) {
+	s := &Schema{document: defaultDoc}
+	if errs := s.Update(sdlText); len(errs) > 0 {
+		return nil, errs
+	}
+	return s, nil
+}
+
+// LoadSchemaFiles parses and validates the types defined in the files matching glob, e.g. "schema/*.graphql".
+func LoadSchemaFiles(glob string) (*Schema, []error) {
+	s := &Schema{document: defaultDoc, glob: glob}
+	if errs := s.Reload(); len(errs) > 0 {
+		return nil, errs
+	}
+	return s, nil
+}
+
+// Version is incremented each time the schema's types are replaced. Nothing in this package is keyed
+// on it: a parser pins the type cache that is current when it is created, so a replacement only
+// applies to parsers created after it.
+func (s *Schema) Version() uint64 {
+	return atomic.LoadUint64(&s.version)
+}
+
+// Update replaces the schema's types with those defined in sdlText.
+func (s *Schema) Update(sdlText string) []error {
+	return s.swap(map[string]string{"": sdlText})
+}
+
+// Reload replaces the schema's types with those defined in its source files.
+func (s *Schema) Reload() []error {
+	if len(s.glob) == 0 {
+		return []error{fmt.Errorf("Schema has no source files to reload")}
+	}
+	files, err := filepath.Glob(s.glob)
+	if err != nil {
+		return []error{err}
+	}
+	if len(files) == 0 {
+		return []error{fmt.Errorf(`No schema files match "%s"`, s.glob)}
+	}
+	sources := make(map[string]string, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return []error{err}
+		}
+		sources[f] = string(b)
+	}
+	return s.swap(sources)
+}
+
+// Watch polls the schema's source files every interval and reloads the schema when they change,
+// until ctx is done. Reload errors are passed to onErr, if assigned.
+func (s *Schema) Watch(ctx context.Context, interval time.Duration, onErr func([]error)) error {
+	if len(s.glob) == 0 {
+		return fmt.Errorf("Schema has no source files to watch")
+	}
+	last := s.fingerprint()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if fp := s.fingerprint(); fp != last {
+					last = fp
+					if errs := s.Reload(); len(errs) > 0 && onErr != nil {
+						onErr(errs)
+					}
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// fingerprint identifies the current state of the schema's source files by name, size and modification time.
+func (s *Schema) fingerprint() string {
+	var fp strings.Builder
+	files, _ := filepath.Glob(s.glob)
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil {
+			fmt.Fprintf(&fp, "%s:%d:%d;", f, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	return fp.String()
+}
+
+// typeCache returns the current types of the schema.
+func (s *Schema) typeCache() *typeCache {
+	return s.tyCache.Load().(*typeCache)
+}
+
+// swap builds and validates a type cache from SDL sources keyed by file name and makes it current.
+func (s *Schema) swap(sources map[string]string) []error {
+
+	s.Lock()
+	defer s.Unlock()
+	tc, errs := buildTypeCache(s.document, sources)
+	if len(errs) > 0 {
+		return errs
+	}
+	s.tyCache.Store(tc)
+	atomic.AddUint64(&s.version, 1)
+	return nil
+}
+
+// builtinSDL declares the directives defined by the GraphQL spec. A schema may redeclare them.
+const builtinSDL = `
+directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @include(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @deprecated(reason: String = "No longer supported") on FIELD_DEFINITION | ENUM_VALUE
+directive @specifiedBy(url: String!) on SCALAR`
+
+// defaultRoots are the root operation types of a schema without a schema statement, in schema statement order.
+var defaultRoots = []struct{ op, name string }{{"query", "Query"}, {"mutation", "Mutation"}, {"subscription", "Subscription"}}
+
+func buildTypeCache(document string, sources map[string]string) (*typeCache, []error) {
+
+	var errs []error
+
+	files := make([]string, 0, len(sources))
+	for f := range sources {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	defined := make(map[string]string) // type name -> file
+	mem := store.NewMemory()
+	for _, f := range files {
+		types, perrs := store.ParseSDL(sources[f])
+		for _, e := range perrs {
+			errs = append(errs, fileErr(f, e))
+		}
+		for name, stmt := range types {
+			if prev, ok := defined[name]; ok {
+				errs = append(errs, fileErr(f, fmt.Errorf(`Type "%s" is already defined%s`, name, inFile(prev))))
+				continue
+			}
+			defined[name] = f
+			mem.AddType(document, name, stmt)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	builtins, _ := store.ParseSDL(builtinSDL)
+	for name, stmt := range builtins {
+		if _, ok := defined[name]; !ok {
+			mem.AddType(document, name, stmt)
+		}
+	}
+	//
+	// a schema without a schema statement has the root operation types named Query, Mutation and Subscription
+	//
+	if _, ok := defined["schema"]; !ok {
+		if _, ok := defined["Query"]; ok {
+			var stmt strings.Builder
+			stmt.WriteString("schema {")
+			for _, r := range defaultRoots {
+				if _, ok := defined[r.name]; ok {
+					fmt.Fprintf(&stmt, " %s: %s", r.op, r.name)
+				}
+			}
+			stmt.WriteString(" }")
+			schema, _ := store.ParseSDL(stmt.String())
+			mem.AddType(document, "schema", schema["schema"])
+		}
+	}
+	tc := newTypeCache(mem, document)
+	if errs = validateTypes(tc, defined); len(errs) > 0 {
+		return nil, errs
+	}
+	tc.complete = true
+	return tc, nil
+}
+
+// validateTypes resolves every type in the schema, confirming all referenced types are defined
+// and the schema statement, or the Query type by default, names an object type as the query root.
+// defined maps each type to its source file.
+func validateTypes(tc *typeCache, defined map[string]string) []error {
+
+	var errs []error
+
+	types := make([]string, 0, len(defined))
+	for name := range defined {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	for _, name := range types {
+		if _, err := tc.FetchAST(sdl.NameValue_(name), logger.Nop()); err != nil {
+			errs = append(errs, fileErr(defined[name], err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, name := range types {
+		ast_, _ := tc.FetchAST(sdl.NameValue_(name), logger.Nop())
+		refs := make(sdl.UnresolvedMap)
+		ast_.SolicitAbstractTypes(refs)
+		for tyName := range refs {
+			if _, err, ok := tc.fetch(tyName.String()); !ok || err != nil {
+				errs = append(errs, fileErr(defined[name], fmt.Errorf(`Type "%s" referenced by "%s" is not defined in the schema`, tyName, name)))
+			}
+		}
+	}
+	schemaAST, _ := tc.FetchAST(sdl.NameValue_("schema"), logger.Nop())
+	schema, ok := schemaAST.(*sdl.Schema_)
+	if !ok {
+		return append(errs, fmt.Errorf(`Schema statement is not defined and there is no "Query" type`))
+	}
+	root, _, _ := tc.fetch(schema.Query.Name.String())
+	if _, ok := root.(*sdl.Object_); !ok {
+		errs = append(errs, fmt.Errorf(`Query root type "%s" is not an object type defined in the schema`, schema.Query.Name))
+	}
+	return errs
+}
+
+func fileErr(file string, err error) error {
+	if len(file) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s: %w", file, err)
+}
+
+func inFile(file string) string {
+	if len(file) == 0 {
+		return ""
+	}
+	return " in " + file
+}
+
+// NewWithSchema returns a parser that validates and executes documents against the current types of schema s.
+func NewWithSchema(l *lexer.Lexer, s *Schema) *Parser {
+	tc := s.typeCache()
+	p := NewWithStore(l, tc.store)
+	p.document = s.document
+	p.schema = s
+	p.tyCache = tc
+	return p
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/store"
+	"github.com/rosshpayne/graphql/tracing"
+)
+
+func TestNewSchema(t *testing.T) {
+
+	s, errs := NewSchema(concurrentSDL)
+	for _, e := range errs {
+		t.Fatalf("Unexpected error: %s", e)
+	}
+	input := `query { hero(episode: JEDI) { id name } }`
+	expectedResult := `{
+        data: {
+             hero : [ 
+             {
+             id : "JEDI-1"
+             name : "Luke"
+             } 
+             {
+             id : "JEDI-2"
+             name : "Leia"
+             } 
+             ] 
+             }
+        }`
+
+	p := NewWithSchema(lexer.New(input), s)
+	if err := p.Resolver.Register("Query/hero", resolveHeroes); err != nil {
+		t.Fatal(err)
+	}
+	_, errs = p.ParseDocument()
+	checkErrors(errs, nil, t)
+	result, errs := p.ExecuteDocument()
+	checkErrors(errs, nil, t)
+	if compare(result, expectedResult) {
+		t.Errorf("Got:      [%s] \n", trimWS(result))
+		t.Errorf("Expected: [%s] \n", trimWS(expectedResult))
+	}
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+
+	inputSDL := `
+	schema {
+		query : Query
+	}
+	type Query { hero(episode: Episode): [Character] }
+	enum Episode { NEWHOPE EMPIRE JEDI }`
+
+	expectedErr := []string{
+		`Type "Character" referenced by "Query" is not defined in the schema`,
+	}
+	_, errs := NewSchema(inputSDL)
+	checkErrors(errs, expectedErr, t)
+
+	_, errs = NewSchema(`type Root { name: String }`)
+	checkErrors(errs, []string{`Schema statement is not defined and there is no "Query" type`}, t)
+}
+
+func TestSchemaDefaults(t *testing.T) {
+
+	s, errs := NewSchema(`
+	type Query { name: String @deprecated(reason: "Use title") title: String }
+	type Mutation { rename(name: String = "a"): String }`)
+	checkErrors(errs, nil, t)
+
+	input := `query Q($all: Boolean = true) { name @include(if: $all) title @skip(if: false) }
+	mutation M { rename(name: "x") }`
+	p := NewWithSchema(lexer.New(input), s)
+	_, errs = p.ParseDocument()
+	checkErrors(errs, nil, t)
+}
+
+func TestLoadSchemaFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.graphql":    `schema { query : Query } type Query { hero(episode: Episode): [Character] }`,
+		"character.graphql": `interface Character { id: String! name: String! } type Human implements Character { id: String! name: String! totalCredits: Int }`,
+		"episode.graphql":   `enum Episode { NEWHOPE EMPIRE JEDI }`,
+	}
+	for f, sdl := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(sdl), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, errs := LoadSchemaFiles(filepath.Join(dir, "*.graphql"))
+	for _, e := range errs {
+		t.Fatalf("Unexpected error: %s", e)
+	}
+	p := NewWithSchema(lexer.New(`query { hero(episode: EMPIRE) { ... on Human { totalCredits } } }`), s)
+	p.Resolver.Register("Query/hero", resolveHeroes)
+	_, errs = p.ParseDocument()
+	checkErrors(errs, nil, t)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "droid.graphql"), []byte(`enum Episode { JEDI }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, errs = LoadSchemaFiles(filepath.Join(dir, "*.graphql"))
+	checkErrors(errs, []string{filepath.Join(dir, "episode.graphql") + `: Type "Episode" is already defined in ` + filepath.Join(dir, "droid.graphql")}, t)
+}
+
+func TestSchemaReload(t *testing.T) {
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.graphql")
+	if err := ioutil.WriteFile(file, []byte(concurrentSDL), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, errs := LoadSchemaFiles(filepath.Join(dir, "*.graphql"))
+	for _, e := range errs {
+		t.Fatalf("Unexpected error: %s", e)
+	}
+	v := s.Version()
+	input := `query { hero(episode: JEDI) { id rank } }`
+	// in flight on the current schema
+	old := NewWithSchema(lexer.New(input), s)
+	//
+	// an invalid schema is not swapped in
+	//
+	if err := ioutil.WriteFile(file, []byte(concurrentSDL+` type Droid { friends: [Starship] }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errs = s.Reload()
+	checkErrors(errs, []string{file + `: Type "Starship" referenced by "Droid" is not defined in the schema`}, t)
+	if s.Version() != v {
+		t.Errorf("Expected version %d got %d", v, s.Version())
+	}
+	//
+	// add field rank to Human and Character
+	//
+	updatedSDL := strings.Replace(concurrentSDL, "name: String!", "name: String!\n\t\trank: Int", -1)
+	if err := ioutil.WriteFile(file, []byte(updatedSDL), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range s.Reload() {
+		t.Fatalf("Unexpected error: %s", e)
+	}
+	if s.Version() != v+1 {
+		t.Errorf("Expected version %d got %d", v+1, s.Version())
+	}
+	_, errs = old.ParseDocument()
+	checkErrors(errs, []string{`Field "rank" is not a member of "hero" (SDL Interface "Character") at line: 1 column: 34`}, t)
+
+	p := NewWithSchema(lexer.New(input), s)
+	_, errs = p.ParseDocument()
+	checkErrors(errs, nil, t)
+}
+
+func TestSchemaWatch(t *testing.T) {
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.graphql")
+	if err := ioutil.WriteFile(file, []byte(concurrentSDL), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, errs := LoadSchemaFiles(filepath.Join(dir, "*.graphql"))
+	for _, e := range errs {
+		t.Fatalf("Unexpected error: %s", e)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the temporary directory is removed after cancel, so errors once ctx is done are expected
+	onErr := func(errs []error) {
+		if ctx.Err() == nil {
+			t.Errorf("Unexpected reload error: %v", errs)
+		}
+	}
+	if err := s.Watch(ctx, 10*time.Millisecond, onErr); err != nil {
+		t.Fatal(err)
+	}
+	v := s.Version()
+	if err := ioutil.WriteFile(file, []byte(concurrentSDL+` type Droid { name: String }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; s.Version() == v; i++ {
+		if i == 200 {
+			t.Fatalf("Schema not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := s.typeCache().FetchAST("Droid", logger.Nop()); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestParserLogger(t *testing.T) {
+
+	s, errs := NewSchema(concurrentSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	var out strings.Builder
+	p := NewWithSchema(lexer.New(`query { hero(episode: JEDI) { id } }`), s)
+	p.SetLogger(logger.New(&out, logger.Debug).With(logger.RequestID, "r1"))
+	p.Resolver.Register("Query/hero", resolveHeroes)
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if _, errs := p.ExecuteDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	for _, msg := range []string{"parsed statement", "check field", "execute resolver", "resolver response"} {
+		if !strings.Contains(out.String(), "DEBUG "+msg+" request_id=r1") {
+			t.Errorf(`Expected message "%s" in log: %s`, msg, out.String())
+		}
+	}
+}
+
+func TestTypeCacheLogger(t *testing.T) {
+
+	m := store.NewMemory()
+	if errs := m.AddSDL(defaultDoc, concurrentSDL); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	// parsers of a store share its type cache, which logs the sourcing of types to the fetching parser's logger
+	for _, id := range []string{"r1", "r2"} {
+		var out strings.Builder
+		p := NewWithStore(lexer.New(`query { hero(episode: JEDI) @missing`+id+` { id } }`), m)
+		p.SetLogger(logger.New(&out, logger.Debug).With(logger.RequestID, id))
+		p.ParseDocument()
+		if !strings.Contains(out.String(), "WARN fetch type request_id="+id) {
+			t.Errorf(`Expected type sourcing of request "%s" in log: %s`, id, out.String())
+		}
+	}
+}
+
+func TestExecuteUnparsed(t *testing.T) {
+
+	p := New(lexer.New(`{ a }`))
+	p.SetLogger(logger.New(ioutil.Discard, logger.Debug))
+	_, errs := p.ExecuteDocument()
+	checkErrors(errs, []string{"Document has not been parsed at line: 1, column: 1"}, t)
+}
+
+func TestParserTracer(t *testing.T) {
+
+	s, errs := NewSchema(concurrentSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	tr := tracing.NewApollo()
+	p := NewWithSchema(lexer.New(`query { hero(episode: JEDI) { id } }`), s)
+	p.SetTracer(tr)
+	p.Resolver.Register("Query/hero", resolveHeroes)
+	if _, errs := p.ParseDocument(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	result, errs := p.ExecuteDocument()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	trace := tr.Trace()
+	if len(trace.Execution.Resolvers) != 1 {
+		t.Fatalf("Expected 1 resolver trace, got %d", len(trace.Execution.Resolvers))
+	}
+	r := trace.Execution.Resolvers[0]
+	if r.ParentType != "Query" || r.FieldName != "hero" || r.ReturnType != "[Character]" || len(r.Path) != 1 || r.Path[0] != "hero" || len(r.Error) > 0 {
+		t.Errorf("Unexpected resolver trace %+v", r)
+	}
+	if trace.Parsing.Duration <= 0 || trace.Validation.StartOffset < trace.Parsing.StartOffset {
+		t.Errorf("Unexpected parse and validation phases %+v %+v", trace.Parsing, trace.Validation)
+	}
+	if !strings.Contains(result, `extensions: {"tracing":{"version":1,`) || !strings.HasSuffix(strings.TrimSpace(result), "}\n}") {
+		t.Errorf("Expected tracing extensions in result: %s", result)
+	}
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const selectionSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		hero(episode: Episode = JEDI): Character
+		search: [SearchResult]
+		episode: Episode
+		count: Int!
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+		friends: [Character]
+		appearsIn: [Episode]!
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+		friends: [Character]
+		appearsIn: [Episode]!
+	}
+	union SearchResult = Human`
+
+func TestSelectionShape(t *testing.T) {
+
+	s, errs := NewSchema(selectionSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `query { hero { name friends { id } appearsIn } search { ... on Human { id } } episode count }`,
+		},
+		{
+			input: `query { hero }`,
+			errs:  []string{`Field "hero" of type "Character" must have a selection of subfields. Did you mean "hero { ... }"? at line: 1 column: 9`},
+		},
+		{
+			input: `query { hero { name friends } }`,
+			errs:  []string{`Field "friends" of type "[Character]" must have a selection of subfields. Did you mean "friends { ... }"? at line: 1 column: 21`},
+		},
+		{
+			input: `query { search }`,
+			errs:  []string{`Field "search" of type "[SearchResult]" must have a selection of subfields. Did you mean "search { ... }"? at line: 1 column: 9`},
+		},
+		{
+			input: `query { hero { name { id } } }`,
+			errs:  []string{`Field "name" must not have a selection since type "String!" has no subfields at line: 1 column: 16`},
+		},
+		{
+			input: `query { episode { name } count }`,
+			errs:  []string{`Field "episode" must not have a selection since type "Episode" has no subfields at line: 1 column: 9`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
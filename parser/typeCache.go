@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	lex "github.com/rosshpayne/graph-sdl/lexer"
+	pse "github.com/rosshpayne/graph-sdl/parser"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/store"
+)
+
+// typeCache holds the AST of the SDL types of a document. Types are sourced from the document's
+// SchemaStore on first reference. A typeCache is shared by all parsers of a store and document.
+type typeCache struct {
+	sync.Mutex
+	store     store.SchemaStore
+	document  string
+	cache     map[string]sdl.GQLTypeProvider
+	notExists map[string]bool // types not found in the store
+	loadMx    sync.Mutex      // serialises the sourcing of types from the store
+	complete  bool            // holds every type of the document, as for a Schema
+}
+
+type typeCacheKey struct {
+	store    store.SchemaStore
+	document string
+}
+
+var (
+	typeCachesMx sync.Mutex
+	typeCaches   = make(map[typeCacheKey]*typeCache)
+)
+
+// getTypeCache returns the type cache of document in store s.
+func getTypeCache(s store.SchemaStore, document string) *typeCache {
+	typeCachesMx.Lock()
+	defer typeCachesMx.Unlock()
+	k := typeCacheKey{s, document}
+	if t, ok := typeCaches[k]; ok {
+		return t
+	}
+	t := newTypeCache(s, document)
+	typeCaches[k] = t
+	return t
+}
+
+// dropTypeCache removes the type cache of document in store s. Parsers holding the cache continue to use it.
+func dropTypeCache(s store.SchemaStore, document string) {
+	typeCachesMx.Lock()
+	delete(typeCaches, typeCacheKey{s, document})
+	typeCachesMx.Unlock()
+}
+
+func newTypeCache(s store.SchemaStore, document string) *typeCache {
+	return &typeCache{store: s, document: document, cache: make(map[string]sdl.GQLTypeProvider), notExists: make(map[string]bool)}
+}
+
+// FetchAST is a concurrency safe access method to the cache. If the type is not cached its SDL
+// statement, and those of any uncached types nested within it, are sourced from the store.
+// Only fully resolved types are added to the cache, so cached ASTs are never modified. The cache is shared
+// by parsers, so the sourcing of types is logged to log of the parser fetching them.
+func (t *typeCache) FetchAST(name sdl.NameValue_, log logger.Logger) (sdl.GQLTypeProvider, error) {
+
+	name_ := name.String()
+	// do not handle scalars or nul name
+	switch name_ {
+	case "String", "Int", "Float", "Boolean", "ID", "null":
+		return nil, pse.ErrnotScalar
+	}
+	if len(name) == 0 {
+		return nil, pse.ErrnoName
+	}
+	if ast_, err, ok := t.fetch(name_); ok {
+		return ast_, err
+	}
+	t.loadMx.Lock()
+	defer t.loadMx.Unlock()
+	// another parser may have sourced the type while waiting on the lock
+	if ast_, err, ok := t.fetch(name_); ok {
+		return ast_, err
+	}
+	loaded := make(map[string]sdl.GQLTypeProvider)
+	ast_, err := t.load(name_, loaded, log)
+	t.Lock()
+	for k, v := range loaded {
+		t.cache[k] = v
+	}
+	t.Unlock()
+
+	return ast_, err
+}
+
+// typeFetcher binds a type cache to the logger of a parser, satisfying ast.TypeFetcher.
+type typeFetcher struct {
+	*typeCache
+	log logger.Logger
+}
+
+func (f typeFetcher) FetchAST(name sdl.NameValue_) (sdl.GQLTypeProvider, error) {
+	return f.typeCache.FetchAST(name, f.log)
+}
+
+// fetch returns the cached AST of a type, or an error if it is known not to exist. ok is false
+// if the type has yet to be sourced from the store.
+func (t *typeCache) fetch(name string) (ast_ sdl.GQLTypeProvider, err error, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+	if ast_, ok = t.cache[name]; ok {
+		return ast_, nil, true
+	}
+	if t.notExists[name] {
+		return nil, t.notFound(name), true
+	}
+	return nil, nil, false
+}
+
+// implementations returns the object types that implement interface name. ok is false when the
+// cache is not complete, as types that have yet to be sourced may also implement it.
+func (t *typeCache) implementations(name sdl.NameValue_) (types []*sdl.Object_, ok bool) {
+	if !t.complete {
+		return nil, false
+	}
+	t.Lock()
+	defer t.Unlock()
+	for _, v := range t.cache {
+		if o, ok := v.(*sdl.Object_); ok {
+			for _, i := range o.Implements {
+				if i.Name == name {
+					types = append(types, o)
+					break
+				}
+			}
+		}
+	}
+	return types, true
+}
+
+func (t *typeCache) notFound(name string) error {
+	return fmt.Errorf(`"%s" %w "%s"`, name, store.ErrNotFound, t.document)
+}
+
+// load sources a type from the store and resolves the abstract (non-scalar) types nested within it,
+// assigning their AST to the type's references. Newly sourced types are saved to loaded.
+func (t *typeCache) load(name string, loaded map[string]sdl.GQLTypeProvider, log logger.Logger) (sdl.GQLTypeProvider, error) {
+
+	if ast_, ok := loaded[name]; ok {
+		return ast_, nil
+	}
+	if ast_, err, ok := t.fetch(name); ok {
+		return ast_, err
+	}
+	typeSDL, err := t.store.FetchType(t.document, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			t.Lock()
+			t.notExists[name] = true
+			t.Unlock()
+		}
+		log.Log(logger.Warn, "fetch type", "document", t.document, "type", name, "error", err)
+		return nil, err
+	}
+	//
+	// generate AST for the type. Source of stmt is the store so its been verified, simply resolve types it refs
+	//
+	p2 := pse.New(lex.New(typeSDL))
+	ast_ := p2.ParseStatement()
+	if errs := p2.Getperror(); len(errs) > 0 {
+		return nil, fmt.Errorf(`Error in SDL of type "%s" in document "%s": %w`, name, t.document, errs[0])
+	}
+	if ast_ == nil {
+		return nil, fmt.Errorf(`Error in SDL of type "%s" in document "%s"`, name, t.document)
+	}
+	loaded[name] = ast_
+
+	nestedAbstractTypes := make(sdl.UnresolvedMap)
+	ast_.SolicitAbstractTypes(nestedAbstractTypes)
+
+	for tyName, gqltype := range nestedAbstractTypes {
+		nested, err := t.load(tyName.String(), loaded, log)
+		if err != nil {
+			log.Log(logger.Warn, "resolve nested type", "document", t.document, "type", tyName, "referencedBy", name, "error", err)
+			continue
+		}
+		if gqltype != nil && gqltype.AST == nil {
+			gqltype.AST = nested
+		}
+	}
+	return ast_, nil
+}
+
+// loadTyCache transfers the cached types to the type table the sdl validation methods reference (sdl.TyCache).
+// The caller must hold sdlMx.
+func (t *typeCache) loadTyCache() {
+	t.Lock()
+	defer t.Unlock()
+	sdl.InitCache(len(t.cache))
+	for k, v := range t.cache {
+		sdl.TyCache[k] = v
+	}
+}
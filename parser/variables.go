@@ -0,0 +1,332 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/ast"
+)
+
+// varUse is a reference, $name, to an operation variable in an argument or input field value.
+// Each reference has its own InputValue_, which stands in for the variable's value and keys
+// the reference in Parser.varUses.
+type varUse struct {
+	name      sdl.NameValue_
+	value     *sdl.InputValue_ // argument value bound to the variable's value or default
+	source    *sdl.InputValue_ // variable value or default currently bound
+	unbound   bool             // variable has no value or default, or is not yet known (fragments)
+	expected  *sdl.GQLtype     // type of the argument or input field the variable is passed to, assigned during validation
+	defaulted bool             // the argument or input field has a default value
+}
+
+// useVariable records a reference to variable name, whose value is val, at the current token.
+func (p *Parser) useVariable(name string, val *sdl.InputValue_) *sdl.InputValue_ {
+	u := &varUse{name: sdl.NameValue_(name), value: &sdl.InputValue_{Loc: p.Loc()}}
+	u.bind(val)
+	p.varUses[u.value] = u
+	p.stmtVarUses = append(p.stmtVarUses, u)
+	return u.value
+}
+
+// bind assigns the value of the variable to the reference. A null value is assigned when the
+// variable has no value or default.
+func (u *varUse) bind(val *sdl.InputValue_) {
+	if u.value.InputValueProvider != nil && val == u.source {
+		// already bound - keep any coercion applied during validation
+		return
+	}
+	u.source = val
+	if val == nil || val.InputValueProvider == nil {
+		u.value.InputValueProvider = sdl.Null_(true)
+		u.unbound = true
+		return
+	}
+	u.value.InputValueProvider = val.InputValueProvider
+	u.unbound = false
+}
+
+// assignVarTypes records the type expected of each variable referenced in v, whose type is t.
+// Variables nested in list and input object values are assigned the type of the list item or input field.
+func (p *Parser) assignVarTypes(v *sdl.InputValue_, t *sdl.GQLtype, defaulted bool) {
+	if v == nil || t == nil {
+		return
+	}
+	if u, ok := p.varUses[v]; ok {
+		u.expected, u.defaulted = t, defaulted
+		return
+	}
+	switch x := v.InputValueProvider.(type) {
+	case sdl.List_:
+		if t.Depth == 0 {
+			return
+		}
+		// item type drops the outermost list and its non-null constraint
+		item := &sdl.GQLtype{Constraint: t.Constraint &^ (1 << t.Depth), AST: t.AST, Depth: t.Depth - 1, Name_: t.Name_, Base: t.Base}
+		for _, e := range x {
+			p.assignVarTypes(e, item, false)
+		}
+	case sdl.ObjectVals:
+		if t.Depth > 0 {
+			return
+		}
+		ast_ := t.AST
+		if ast_ == nil {
+			ast_, _ = p.tyCache.FetchAST(t.Name, p.log)
+		}
+		in, ok := ast_.(*sdl.Input_)
+		if !ok {
+			return
+		}
+		for _, a := range x {
+			for _, d := range in.InputValueDefs {
+				if a.Name_.Equals(d.Name_) {
+					p.assignVarTypes(a.Value, d.Type, d.DefaultVal != nil)
+					break
+				}
+			}
+		}
+	}
+}
+
+// hasUnboundVar reports whether v references, directly or in a list or input object value, a variable without a value.
+func (p *Parser) hasUnboundVar(v *sdl.InputValue_) bool {
+	if v == nil {
+		return false
+	}
+	if u, ok := p.varUses[v]; ok {
+		return u.unbound
+	}
+	switch x := v.InputValueProvider.(type) {
+	case sdl.List_:
+		for _, e := range x {
+			if p.hasUnboundVar(e) {
+				return true
+			}
+		}
+	case sdl.ObjectVals:
+		for _, a := range x {
+			if p.hasUnboundVar(a.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// operationVarUses returns the variable references of op and of the fragments it spreads, directly or indirectly.
+func (p *Parser) operationVarUses(op *ast.OperationStmt) []*varUse {
+	var (
+		uses    = append([]*varUse(nil), p.varUsesByStmt[op]...)
+		visited = make(map[sdl.NameValue_]bool)
+		walk    func(set []ast.SelectionSetProvider)
+	)
+	walk = func(set []ast.SelectionSetProvider) {
+		for _, s := range set {
+			switch x := s.(type) {
+			case *ast.Field:
+				walk(x.SelectionSet)
+			case *ast.InlineFragment:
+				walk(x.SelectionSet)
+			case *ast.FragmentSpread:
+				if visited[x.Name] {
+					continue
+				}
+				visited[x.Name] = true
+				frag := x.FragStmt
+				if frag == nil {
+					frag = p.fragmentStmts[x.Name]
+				}
+				if frag == nil {
+					continue
+				}
+				uses = append(uses, p.varUsesByStmt[frag]...)
+				walk(frag.SelectionSet)
+			}
+		}
+	}
+	walk(op.SelectionSet)
+	return uses
+}
+
+// bindVariables binds the variable references of op, including those in the fragments it spreads,
+// to the values of op's variables. Fragments are shared by operations, so they are rebound before
+// each operation is validated or executed.
+func (p *Parser) bindVariables(op *ast.OperationStmt) []*varUse {
+	uses := p.operationVarUses(op)
+	for _, u := range uses {
+		var val *sdl.InputValue_
+		for _, v := range op.Variable {
+			if v.Name == u.name {
+				if val = v.Value; val == nil {
+					val = v.DefaultVal
+				}
+				break
+			}
+		}
+		u.bind(val)
+	}
+	return uses
+}
+
+// checkVariables validates the variables of op: names are unique, each variable is used, each
+// variable referenced (including from fragments) is defined, and each reference is passed to a
+// position whose type is compatible with the variable's type.
+func (p *Parser) checkVariables(op *ast.OperationStmt) {
+
+	var by string
+	if nm := operationName(op.Name.String()); len(nm) > 0 {
+		by = fmt.Sprintf(` by operation "%s"`, nm)
+	}
+	defs := make(map[sdl.NameValue_]*ast.VariableDef)
+	for _, v := range op.Variable {
+		if _, ok := defs[v.Name]; ok {
+			p.addErr(fmt.Sprintf(`Variable "$%s" is defined more than once %s`, v.Name, v.Name_.AtPosition()))
+			continue
+		}
+		defs[v.Name] = v
+	}
+	used := make(map[sdl.NameValue_]bool)
+	for _, u := range p.operationVarUses(op) {
+		def, ok := defs[u.name]
+		if !ok {
+			p.addErr(fmt.Sprintf(`Variable "$%s" is not defined%s %s`, u.name, by, u.value.AtPosition()))
+			continue
+		}
+		used[u.name] = true
+		if u.expected != nil && !u.allowed(def) {
+			p.addErr(fmt.Sprintf(`Variable "$%s" of type "%s" used in position expecting type "%s" %s`, u.name, def.Type.String(), u.expected.String(), u.value.AtPosition()))
+		}
+	}
+	for _, v := range op.Variable {
+		if !used[v.Name] && defs[v.Name] == v {
+			p.addErr(fmt.Sprintf(`Variable "$%s" is not used%s %s`, v.Name, by, v.Name_.AtPosition()))
+		}
+	}
+}
+
+// checkVariableValues reports each variable of op that may not be null but has no value or default.
+// It is checked when op is executed, as the document may be validated before its variable values are known.
+// A null value is rejected by the validation of the argument the variable is passed to.
+func (p *Parser) checkVariableValues(op *ast.OperationStmt) {
+	for _, v := range op.Variable {
+		if v.Type.Constraint>>v.Type.Depth&1 == 1 && v.Value == nil && v.DefaultVal == nil {
+			p.addErr(fmt.Sprintf(`Variable "$%s" of required type "%s" was not provided %s`, v.Name, v.Type.String(), v.Name_.AtPosition()))
+		}
+	}
+}
+
+// allowed reports whether variable def may be passed to the position of the reference.
+// A nullable variable may be passed to a non-null position when either has a default value.
+func (u *varUse) allowed(def *ast.VariableDef) bool {
+	v, l := def.Type, u.expected
+	lc := l.Constraint
+	if lc>>l.Depth&1 == 1 && v.Constraint>>v.Depth&1 == 0 {
+		if !u.defaulted && (def.DefaultVal == nil || def.DefaultVal.InputValueProvider == sdl.Null_(true)) {
+			return false
+		}
+		lc &^= 1 << l.Depth
+	}
+	return compatibleTypes(v.Constraint, v.Depth, lc, l.Depth) && v.Name == l.Name
+}
+
+// compatibleTypes compares the list and non-null wrapping of a variable type with the type of the
+// position it is passed to. The non-null constraint of list depth d is bit d of the constraint.
+func compatibleTypes(vc byte, vd uint8, lc byte, ld uint8) bool {
+	for {
+		if lc>>ld&1 == 1 && vc>>vd&1 == 0 {
+			return false
+		}
+		if ld == 0 || vd == 0 {
+			return ld == vd
+		}
+		vd--
+		ld--
+	}
+}
+
+// SetVariables assigns the values of the operation variables of the documents parsed next, by variable
+// name. Values are those decoded from JSON by encoding/json, preferably using json.Decoder.UseNumber.
+// A string is an enum value wherever the type of the variable, list item or input field is an enum.
+func (p *Parser) SetVariables(vars map[string]interface{}) {
+	p.variables = vars
+}
+
+// variableValue converts v, a JSON value of type t supplied by SetVariables, to an input value at loc.
+func (p *Parser) variableValue(v interface{}, t *sdl.GQLtype, loc *sdl.Loc_) *sdl.InputValue_ {
+	iv := &sdl.InputValue_{Loc: loc}
+	switch x := v.(type) {
+	case nil:
+		iv.InputValueProvider = sdl.Null_(true)
+	case bool:
+		iv.InputValueProvider = sdl.Bool_(x)
+	case json.Number:
+		if strings.ContainsAny(string(x), ".eE") {
+			iv.InputValueProvider = sdl.Float_(x)
+		} else {
+			iv.InputValueProvider = sdl.Int_(x)
+		}
+	case float64:
+		if s := strconv.FormatFloat(x, 'f', -1, 64); !strings.Contains(s, ".") {
+			iv.InputValueProvider = sdl.Int_(s)
+		} else {
+			iv.InputValueProvider = sdl.Float_(s)
+		}
+	case string:
+		if _, ok := p.namedType(t).(*sdl.Enum_); ok {
+			e := &sdl.EnumValue_{}
+			e.AssignName(x, loc, &p.perror)
+			iv.InputValueProvider = e
+		} else {
+			iv.InputValueProvider = sdl.String_(x)
+		}
+	case []interface{}:
+		item := t
+		if t.Depth > 0 {
+			// item type drops the outermost list and its non-null constraint
+			item = &sdl.GQLtype{Constraint: t.Constraint &^ (1 << t.Depth), AST: t.AST, Depth: t.Depth - 1, Name_: t.Name_, Base: t.Base}
+		}
+		l := sdl.List_{}
+		for _, e := range x {
+			l = append(l, p.variableValue(e, item, loc))
+		}
+		iv.InputValueProvider = l
+	case map[string]interface{}:
+		in, _ := p.namedType(t).(*sdl.Input_)
+		names := make([]string, 0, len(x))
+		for k := range x {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		var vals sdl.ObjectVals
+		for _, k := range names {
+			ft := &sdl.GQLtype{}
+			if in != nil {
+				for _, d := range in.InputValueDefs {
+					if d.Name_.EqualString(k) {
+						ft = d.Type
+						break
+					}
+				}
+			}
+			vals = append(vals, &sdl.ArgumentT{Name_: sdl.Name_{Name: sdl.NameValue_(k), Loc: loc}, Value: p.variableValue(x[k], ft, loc)})
+		}
+		iv.InputValueProvider = vals
+	default:
+		p.addErr(fmt.Sprintf(`Unsupported variable value %v of type %T %s`, x, x, iv.AtPosition()))
+		iv.InputValueProvider = sdl.Null_(true)
+	}
+	return iv
+}
+
+// namedType returns the SDL definition of the named type of t, or nil for a scalar or unknown type.
+func (p *Parser) namedType(t *sdl.GQLtype) sdl.GQLTypeProvider {
+	if t.AST != nil || len(t.Name) == 0 || t.IsScalar() || p.tyCache == nil {
+		return t.AST
+	}
+	ast_, _ := p.tyCache.FetchAST(t.Name, p.log)
+	return ast_
+}
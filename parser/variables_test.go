@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/lexer"
+)
+
+const variablesSDL = `
+	schema {
+		query : Query
+	}
+	type Query {
+		hero(episode: Episode!): [Character]
+		droid(episode: Episode): [Character]
+		heroes(ids: [Int!]): [Character]
+		search(filter: HeroFilter): [Character]
+		page(limit: Int = 10): [Character]
+	}
+	input HeroFilter {
+		name: String!
+		rank: Int
+	}
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: String!
+		name: String!
+	}
+	type Human implements Character {
+		id: String!
+		name: String!
+		totalCredits: Int
+	}`
+
+func TestVariables(t *testing.T) {
+
+	s, errs := NewSchema(variablesSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input string
+		errs  []string
+	}{
+		{
+			input: `query Q($ep: Episode!, $ids: [Int!], $id: Int!) { hero(episode: $ep) { id } droid(episode: $ep) { id } heroes(ids: $ids) { id } search: heroes(ids: [$id]) { name } }`,
+		},
+		{ // nullable variables are allowed in non-null positions when either has a default
+			input: `query Q($ep: Episode = JEDI, $limit: Int) { hero(episode: $ep) { id } page(limit: $limit) { id } }`,
+		},
+		{
+			input: `query Q($ep: Episode!, $rank: Int) { hero(episode: $ep) { id } }`,
+			errs:  []string{`Variable "$rank" is not used by operation "Q" at line: 1 column: 25`},
+		},
+		{
+			input: `query ($ep: Episode!, $ep: Episode!) { hero(episode: $ep) { id } }`,
+			errs:  []string{`Variable "$ep" is defined more than once at line: 1 column: 24`},
+		},
+		{
+			input: `query Q($ep: Episode) { hero(episode: $ep) { id } }`,
+			errs:  []string{`Variable "$ep" of type "Episode" used in position expecting type "Episode!" at line: 1 column: 40`},
+		},
+		{
+			input: `query Q($ids: [Int]) { heroes(ids: $ids) { id } }`,
+			errs:  []string{`Variable "$ids" of type "[Int]" used in position expecting type "[Int!]" at line: 1 column: 37`},
+		},
+		{
+			input: `query Q($id: String) { heroes(ids: [$id]) { id } }`,
+			errs:  []string{`Variable "$id" of type "String" used in position expecting type "Int!" at line: 1 column: 38`},
+		},
+		{
+			input: `query Q($name: String) { search(filter: {name: $name}) { id } }`,
+			errs:  []string{`Variable "$name" of type "String" used in position expecting type "String!" at line: 1 column: 49`},
+		},
+		{
+			input: `query Q($name: String!) { search(filter: {name: $name}) { id } }`,
+		},
+		{ // variables used in fragments
+			input: `query Q($ep: Episode!) { ...heroes }
+			        fragment heroes on Query { hero(episode: $ep) { id } }`,
+		},
+		{
+			input: `fragment heroes on Query { hero(episode: $ep) { ...names } }
+			        fragment names on Character { id }
+			        query Q($ep: Episode) { ...heroes }`,
+			errs: []string{`Variable "$ep" of type "Episode" used in position expecting type "Episode!" at line: 1 column: 43`},
+		},
+		{
+			input: `query Q { ...heroes }
+			        fragment heroes on Query { hero(episode: $ep) { id } }`,
+			errs: []string{`Variable "$ep" is not defined by operation "Q" at line: 2 column: 54`},
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+	}
+}
+
+func TestFragmentVariableValues(t *testing.T) {
+
+	s, errs := NewSchema(variablesSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	// the fragment is parsed before the operation that defines its variable
+	input := `fragment heroes on Query { droid(episode: $ep) { id } }
+	          query Q($ep: Episode = EMPIRE) { ...heroes }`
+
+	p := NewWithSchema(lexer.New(input), s)
+	_, errs = p.ParseDocument()
+	checkErrors(errs, nil, t)
+	droid := p.fragmentStmts["heroes"].SelectionSet[0].(*ast.Field)
+	if got := droid.Arguments[0].Value.String(); got != "EMPIRE" {
+		t.Errorf(`Expected argument value "EMPIRE" got "%s"`, got)
+	}
+}
+
+func TestVariableValues(t *testing.T) {
+
+	s, errs := NewSchema(variablesSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input     string
+		variables string
+		value     string // of the first argument of the first field
+		errs      []string
+	}{
+		{
+			input:     `query Q($ep: Episode!) { hero(episode: $ep) { id } }`,
+			variables: `{"ep": "EMPIRE"}`,
+			value:     "EMPIRE",
+		},
+		{
+			input:     `query Q($ids: [Int!]) { heroes(ids: $ids) { id } }`,
+			variables: `{"ids": [1, 2]}`,
+		},
+		{
+			input:     `query Q($f: HeroFilter) { search(filter: $f) { id } }`,
+			variables: `{"f": {"name": "Luke", "rank": 2}}`,
+		},
+		{ // the value given replaces the default
+			input:     `query Q($limit: Int = 10) { page(limit: $limit) { id } }`,
+			variables: `{"limit": 3, "unused": true}`,
+			value:     "3",
+		},
+		{
+			input:     `query Q($ep: Episode!) { hero(episode: $ep) { id } }`,
+			variables: `{"ep": 3}`,
+			errs:      []string{`Required type for argument "episode" is Enum, got Int at line: 1 column: 31`},
+		},
+	} {
+		d := json.NewDecoder(strings.NewReader(v.variables))
+		d.UseNumber()
+		var vars map[string]interface{}
+		if err := d.Decode(&vars); err != nil {
+			t.Fatal(err)
+		}
+		p := NewWithSchema(lexer.New(v.input), s)
+		p.SetVariables(vars)
+		_, errs := p.ParseDocument()
+		checkErrors(errs, v.errs, t)
+		if len(v.value) == 0 || len(errs) > 0 {
+			continue
+		}
+		f := p.operationStmts["Q"].SelectionSet[0].(*ast.Field)
+		if got := f.Arguments[0].Value.String(); got != v.value {
+			t.Errorf(`Expected argument value "%s" got "%s"`, v.value, got)
+		}
+	}
+}
+
+func TestRequiredVariables(t *testing.T) {
+
+	s, errs := NewSchema(variablesSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	for _, v := range []struct {
+		input     string
+		variables map[string]interface{}
+		err       string
+	}{
+		{
+			input: `query Q($ep: Episode!) { hero(episode: $ep) { id } }`,
+			err:   `Variable "$ep" of required type "Episode!" was not provided at line: 1 column: 10`,
+		},
+		{
+			input:     `query Q($ep: Episode!) { hero(episode: $ep) { id } }`,
+			variables: map[string]interface{}{},
+			err:       `Variable "$ep" of required type "Episode!" was not provided at line: 1 column: 10`,
+		},
+		{
+			input:     `query Q($ep: Episode!) { hero(episode: $ep) { id } }`,
+			variables: map[string]interface{}{"ep": "JEDI"},
+		},
+		{
+			input: `query Q($ep: Episode! = JEDI) { hero(episode: $ep) { id } }`,
+		},
+	} {
+		p := NewWithSchema(lexer.New(v.input), s)
+		p.SetVariables(v.variables)
+		p.Resolver.Register("Query/hero", resolveHeroes)
+		if _, errs := p.ParseDocument(); len(errs) > 0 {
+			t.Errorf("Unexpected errors validating %s: %v", v.input, errs)
+			continue
+		}
+		_, errs := p.ExecuteDocument()
+		var expected []string
+		if len(v.err) > 0 {
+			expected = []string{v.err}
+		}
+		checkErrors(errs, expected, t)
+	}
+}
@@ -0,0 +1,409 @@
+// Package printer prints GraphQL documents in a canonical layout. The output re-parses to the
+// same document, and printing it again produces the same text.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/ast"
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/parser"
+	"github.com/rosshpayne/graphql/token"
+)
+
+// shorthand prefixes the name the parser gives operations without one.
+const shorthand = "__NONAME__"
+
+// Config controls the layout of printed documents.
+type Config struct {
+	Indent   string // indentation of each level of selection set. Two spaces when empty.
+	Comments bool   // Source keeps the comments of the source
+}
+
+// DefaultConfig is the configuration of Fprint and Source.
+var DefaultConfig = Config{Indent: "  ", Comments: true}
+
+// Fprint writes doc to w using DefaultConfig.
+func Fprint(w io.Writer, doc *ast.Document) error {
+	return DefaultConfig.Fprint(w, doc)
+}
+
+// Source formats the GraphQL document src using DefaultConfig.
+func Source(src []byte) ([]byte, []error) {
+	return DefaultConfig.Source(src)
+}
+
+// Fprint writes doc to w. Documents parsed with Parser.ParseDocument have their variables
+// substituted by their values, so print a document from Parser.Parse to reproduce its source.
+func (c *Config) Fprint(w io.Writer, doc *ast.Document) error {
+	p := c.newPrinter()
+	p.document(doc)
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// Source formats the GraphQL document src, which must parse without errors. Comments are kept
+// when c.Comments is set, each before the selection that follows it or at the end of its line.
+func (c *Config) Source(src []byte) ([]byte, []error) {
+	doc, errs := parser.NewWithStore(lexer.New(string(src)), nil).Parse()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p := c.newPrinter()
+	if c.Comments {
+		p.scan(string(src))
+	}
+	p.document(doc)
+	return p.buf.Bytes(), nil
+}
+
+type printer struct {
+	indent string
+	buf    bytes.Buffer
+	depth  int  // indentation of the current line
+	open   bool // current line is not yet terminated, so a trailing comment may follow
+	start  int  // offset in buf of the text of the current line
+	sep    bool // a blank line is due before the next line
+	//
+	// source layout, assigned by scan
+	//
+	comments []comment
+	tops     []token.Pos             // { of each statement's selection set
+	sets     []token.Pos             // { of every selection set, in source order
+	closes   map[token.Pos]token.Pos // } of each selection set by its {
+}
+
+// comment is a comment of the source, either on a line of its own or at the end of a line.
+type comment struct {
+	text     string
+	loc      token.Pos
+	trailing bool
+}
+
+func (c *Config) newPrinter() *printer {
+	p := &printer{indent: c.Indent}
+	if len(p.indent) == 0 {
+		p.indent = "  "
+	}
+	return p
+}
+
+// scan lexes src for its comments and the position of the braces of each selection set.
+// Braces inside arguments, variable definitions and list values enclose input object values.
+func (p *printer) scan(src string) {
+	l := lexer.New(src)
+	l.SetTrivia(true)
+	p.closes = make(map[token.Pos]token.Pos)
+	var (
+		values int         // open ( and [
+		braces []token.Pos // open {, with a zero position for input object values
+	)
+	for {
+		tok := l.NextToken()
+		for _, t := range tok.Leading {
+			if t.Type == token.COMMENT {
+				p.comments = append(p.comments, comment{text: t.Literal, loc: t.Loc})
+			}
+		}
+		for _, t := range tok.Trailing {
+			if t.Type == token.COMMENT {
+				p.comments = append(p.comments, comment{text: t.Literal, loc: t.Loc, trailing: true})
+			}
+		}
+		switch tok.Type {
+		case token.EOF:
+			return
+		case token.LPAREN, token.LBRACKET:
+			values++
+		case token.RPAREN, token.RBRACKET:
+			values--
+		case token.LBRACE:
+			if values > 0 {
+				braces = append(braces, token.Pos{})
+				break
+			}
+			if len(braces) == 0 {
+				p.tops = append(p.tops, tok.Loc)
+			}
+			p.sets = append(p.sets, tok.Loc)
+			braces = append(braces, tok.Loc)
+		case token.RBRACE:
+			if len(braces) == 0 {
+				break
+			}
+			if open := braces[len(braces)-1]; open.Line > 0 {
+				p.closes[open] = tok.Loc
+			}
+			braces = braces[:len(braces)-1]
+		}
+	}
+}
+
+func before(a, b token.Pos) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Col < b.Col
+}
+
+// pos returns the source position of loc, or a zero position when the node has none.
+func pos(loc *sdl.Loc_) token.Pos {
+	if loc == nil {
+		return token.Pos{}
+	}
+	return token.Pos{Line: loc.Line, Col: loc.Column}
+}
+
+// opening returns the position of the { of the first selection set after at.
+func (p *printer) opening(at token.Pos) token.Pos {
+	i := sort.Search(len(p.sets), func(i int) bool { return before(at, p.sets[i]) })
+	if at.Line == 0 || i == len(p.sets) {
+		return token.Pos{}
+	}
+	return p.sets[i]
+}
+
+// flush prints the comments before position at. A zero position prints none.
+func (p *printer) flush(at token.Pos) {
+	for len(p.comments) > 0 && before(p.comments[0].loc, at) {
+		c := p.comments[0]
+		p.comments = p.comments[1:]
+		if c.trailing && p.open {
+			p.buf.WriteString(" " + c.text)
+			continue
+		}
+		p.line()
+		p.buf.WriteString(c.text)
+	}
+}
+
+// line starts a new line at the current indentation.
+func (p *printer) line() {
+	if p.open {
+		p.buf.WriteByte('\n')
+	}
+	if p.sep {
+		p.buf.WriteByte('\n')
+		p.sep = false
+	}
+	p.buf.WriteString(strings.Repeat(p.indent, p.depth))
+	p.open, p.start = true, p.buf.Len()
+}
+
+func (p *printer) document(doc *ast.Document) {
+	for i, stmt := range doc.Statements {
+		p.sep = i > 0
+		var top token.Pos
+		if i < len(p.tops) {
+			top = p.tops[i]
+		}
+		p.flush(top)
+		p.line()
+		switch s := stmt.AST.(type) {
+		case *ast.OperationStmt:
+			p.operation(s)
+			p.selectionSet(s.SelectionSet, top)
+		case *ast.FragmentStmt:
+			fmt.Fprintf(&p.buf, "fragment %s on %s", s.Name, s.TypeCond)
+			p.directives(s.Directives)
+			p.selectionSet(s.SelectionSet, top)
+		}
+	}
+	p.flush(token.Pos{Line: int(^uint(0) >> 1)})
+	if p.open {
+		p.buf.WriteByte('\n')
+	}
+}
+
+func (p *printer) operation(s *ast.OperationStmt) {
+	named := !strings.HasPrefix(s.Name.String(), shorthand)
+	if s.Type == "query" && !named && len(s.Variable) == 0 && len(s.Directives) == 0 {
+		// shorthand query
+		return
+	}
+	p.buf.WriteString(s.Type)
+	if named {
+		p.buf.WriteString(" " + s.Name.String())
+	}
+	if len(s.Variable) > 0 {
+		p.buf.WriteString("(")
+		for i, v := range s.Variable {
+			if i > 0 {
+				p.buf.WriteString(", ")
+			}
+			fmt.Fprintf(&p.buf, "$%s: %s", v.Name, v.Type.String())
+			if v.DefaultVal != nil {
+				p.buf.WriteString(" = ")
+				p.value(v.DefaultVal)
+			}
+		}
+		p.buf.WriteString(")")
+	}
+	p.directives(s.Directives)
+}
+
+// selectionSet prints a selection set at the end of the current line. open is the position of
+// its { in the source, or a zero position when the source is not known.
+func (p *printer) selectionSet(ss []ast.SelectionSetProvider, open token.Pos) {
+	if p.buf.Len() > p.start {
+		p.buf.WriteString(" ")
+	}
+	p.buf.WriteString("{")
+	p.depth++
+	for _, s := range ss {
+		switch x := s.(type) {
+		case *ast.Field:
+			at := pos(x.Name.Loc)
+			if x.Alias.Exists() {
+				at = pos(x.Alias.Loc)
+			}
+			p.flush(at)
+			p.line()
+			if x.Alias.Exists() {
+				p.buf.WriteString(x.Alias.String() + ": ")
+			}
+			p.buf.WriteString(x.Name.String())
+			p.arguments(x.Arguments)
+			p.directives(x.Directives)
+			if len(x.SelectionSet) > 0 {
+				p.selectionSet(x.SelectionSet, p.opening(at))
+			}
+		case *ast.FragmentSpread:
+			p.flush(pos(x.Loc))
+			p.line()
+			p.buf.WriteString("..." + x.Name.String())
+			p.directives(x.Directives)
+		case *ast.InlineFragment:
+			at := pos(x.Loc)
+			p.flush(at)
+			p.line()
+			p.buf.WriteString("...")
+			if x.TypeCond.Exists() {
+				p.buf.WriteString(" on " + x.TypeCond.String())
+			}
+			p.directives(x.Directives)
+			p.selectionSet(x.SelectionSet, p.opening(at))
+		}
+	}
+	if open.Line > 0 {
+		p.flush(p.closes[open])
+	}
+	p.depth--
+	p.line()
+	p.buf.WriteString("}")
+}
+
+func (p *printer) arguments(args []*sdl.ArgumentT) {
+	if len(args) == 0 {
+		return
+	}
+	p.buf.WriteString("(")
+	for i, a := range args {
+		if i > 0 {
+			p.buf.WriteString(", ")
+		}
+		p.buf.WriteString(a.Name.String() + ": ")
+		p.value(a.Value)
+	}
+	p.buf.WriteString(")")
+}
+
+func (p *printer) directives(dirs []*sdl.DirectiveT) {
+	for _, d := range dirs {
+		// validation prefixes the names of directives with @
+		p.buf.WriteString(" @" + strings.TrimPrefix(d.Name.String(), "@"))
+		p.arguments(d.Arguments)
+	}
+}
+
+func (p *printer) value(v *sdl.InputValue_) {
+	if v == nil {
+		p.buf.WriteString("null")
+		return
+	}
+	switch x := v.InputValueProvider.(type) {
+	case nil, sdl.Null_:
+		p.buf.WriteString("null")
+	case sdl.String_:
+		p.buf.WriteString(quote(string(x)))
+	case sdl.RawString_:
+		p.blockString(string(x))
+	case *sdl.EnumValue_:
+		p.buf.WriteString(x.Name.String())
+	case sdl.List_:
+		p.buf.WriteString("[")
+		for i, e := range x {
+			if i > 0 {
+				p.buf.WriteString(", ")
+			}
+			p.value(e)
+		}
+		p.buf.WriteString("]")
+	case sdl.ObjectVals:
+		p.buf.WriteString("{")
+		for i, a := range x {
+			if i > 0 {
+				p.buf.WriteString(", ")
+			}
+			p.buf.WriteString(a.Name.String() + ": ")
+			p.value(a.Value)
+		}
+		p.buf.WriteString("}")
+	default:
+		// Int_, Float_, Bool_ and ast.Variable_ print as written
+		p.buf.WriteString(x.String())
+	}
+}
+
+// quote returns s as a GraphQL string value.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// blockString prints s as a block string. A value of more than one line, or one that would run
+// into the closing quotes, is printed on lines of its own at the indentation of the current line.
+func (p *printer) blockString(s string) {
+	s = strings.ReplaceAll(s, `"""`, `\"""`)
+	if !strings.ContainsAny(s, "\n\r") && !strings.HasSuffix(s, `"`) && !strings.HasSuffix(s, `\`) {
+		p.buf.WriteString(`"""` + s + `"""`)
+		return
+	}
+	indent := strings.Repeat(p.indent, p.depth)
+	p.buf.WriteString(`"""`)
+	for _, ln := range strings.Split(s, "\n") {
+		p.buf.WriteString("\n")
+		if len(ln) > 0 {
+			p.buf.WriteString(indent + ln)
+		}
+	}
+	p.buf.WriteString("\n" + indent + `"""`)
+}
@@ -0,0 +1,167 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/parser"
+)
+
+func TestSource(t *testing.T) {
+
+	for _, v := range []struct {
+		input  string
+		result string
+	}{
+		{
+			input: `{hero{name friends{id}}}`,
+			result: `{
+  hero {
+    name
+    friends {
+      id
+    }
+  }
+}
+`,
+		},
+		{
+			input: `query Hero($ep:Episode=JEDI,$ids:[ID!]!=[]) @live {hero(episode:$ep ids:$ids) {name}}`,
+			result: `query Hero($ep: Episode = JEDI, $ids: [ID!]! = []) @live {
+  hero(episode: $ep, ids: $ids) {
+    name
+  }
+}
+`,
+		},
+		{
+			input: `mutation { like(input: {id: "1" note: "say \"hi\"\n" tags: [a b] rank: 1.5 top: false}) { count } }`,
+			result: `mutation {
+  like(input: {id: "1", note: "say \"hi\"\n", tags: [a, b], rank: 1.5, top: false}) {
+    count
+  }
+}
+`,
+		},
+		{
+			input: `query { hero { pilot: name ...HeroFields @include(if: $all) ... on Human { height } ... @skip(if: true) { id } } }
+fragment HeroFields on Character { id }`,
+			result: `{
+  hero {
+    pilot: name
+    ...HeroFields @include(if: $all)
+    ... on Human {
+      height
+    }
+    ... @skip(if: true) {
+      id
+    }
+  }
+}
+
+fragment HeroFields on Character {
+  id
+}
+`,
+		},
+		{
+			input: `{ hero { text(short: """one line""" long: """
+				first
+				  second
+			""") } }`,
+			result: `{
+  hero {
+    text(short: """one line""", long: """
+    first
+      second
+    """)
+  }
+}
+`,
+		},
+		{
+			input: `# heroes
+query Hero { # the hero
+	hero {
+		name # full name
+
+		# friends of the hero
+		friends { id }
+		# no more
+	}
+} # end of Hero
+# names
+{ name }`,
+			result: `# heroes
+query Hero { # the hero
+  hero {
+    name # full name
+    # friends of the hero
+    friends {
+      id
+    }
+    # no more
+  }
+} # end of Hero
+
+# names
+{
+  name
+}
+`,
+		},
+	} {
+		result, errs := Source([]byte(v.input))
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if string(result) != v.result {
+			t.Errorf("Got:\n%s\nExpected:\n%s", result, v.result)
+		}
+		// canonical output prints unchanged
+		again, errs := Source(result)
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if !bytes.Equal(again, result) {
+			t.Errorf("Printed output is not stable. Got:\n%s\nExpected:\n%s", again, result)
+		}
+	}
+}
+
+func TestSourceConfig(t *testing.T) {
+
+	input := `query Hero { # the hero
+	hero { name }
+}`
+	c := Config{Indent: "\t"}
+	result, errs := c.Source([]byte(input))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	expected := "query Hero {\n\thero {\n\t\tname\n\t}\n}\n"
+	if string(result) != expected {
+		t.Errorf("Got:\n%s\nExpected:\n%s", result, expected)
+	}
+
+	if _, errs := Source([]byte(`{ hero { name }`)); len(errs) == 0 {
+		t.Errorf("Expected an error for a selection set without its closing brace")
+	}
+}
+
+func TestFprint(t *testing.T) {
+
+	doc, errs := parser.NewWithStore(lexer.New(`query Hero($ep: Episode) { hero(episode: $ep) { name } }`), nil).Parse()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	var b bytes.Buffer
+	if err := Fprint(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := "query Hero($ep: Episode) {\n  hero(episode: $ep) {\n    name\n  }\n}\n"
+	if b.String() != expected {
+		t.Errorf("Got:\n%s\nExpected:\n%s", b.String(), expected)
+	}
+}
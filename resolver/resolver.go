@@ -19,6 +19,32 @@ type ResolverFunc func(context.Context, sdl.InputValueProvider, sdl.ObjectVals)
 
 type resolverPath string
 
+// Field is the field being resolved, as presented to middleware.
+type Field struct {
+	Path       string                 // path of the field from the root type, e.g. "Query/hero/name"
+	ParentType string                 // type the field belongs to
+	Name       string                 // field name, which is also its name in the parent value
+	Alias      string                 // empty if the field has no alias
+	Arguments  sdl.ObjectVals         // argument values of the field
+	Parent     sdl.InputValueProvider // response data of the parent field, nil for a root field without one
+	Resolver   ResolverFunc           // resolver registered for the field, nil when it is resolved from Parent
+}
+
+// FieldResolveFn resolves the value of a field. The value is nil if the field has none.
+type FieldResolveFn func(ctx context.Context, f *Field) (sdl.InputValueProvider, error)
+
+// Middleware wraps the resolution of every field. It may inspect or amend the field before calling next,
+// inspect or replace the value next returns, or return an error without calling next.
+type Middleware func(next FieldResolveFn) FieldResolveFn
+
+// Chain returns fn wrapped by middleware mw, the first of which runs outermost.
+func Chain(fn FieldResolveFn, mw ...Middleware) FieldResolveFn {
+	for i := len(mw) - 1; i >= 0; i-- {
+		fn = mw[i](fn)
+	}
+	return fn
+}
+
 type Resolvers struct {
 	resolverMap map[resolverPath]ResolverFunc
 }
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/parser"
+)
+
+const (
+	// DocumentHeader names the document of a request. It takes precedence over the URL path.
+	DocumentHeader = "X-GraphQL-Document"
+	// RequestIDHeader carries the request ID logged with each message of a request.
+	// One is generated when the header is absent.
+	RequestIDHeader = "X-Request-ID"
+)
+
+var (
+	requestSeq    uint64
+	requestPrefix = fmt.Sprintf("%x", time.Now().Unix())
+)
+
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); len(id) > 0 {
+		return id
+	}
+	return fmt.Sprintf("%s-%d", requestPrefix, atomic.AddUint64(&requestSeq, 1))
+}
+
+type request struct {
+	Query string `json:"query"`
+}
+
+// Handler serves GraphQL requests from the registry. The document is taken from the DocumentHeader
+// or, if absent, the URL path, so a handler mounted with
+//
+//	http.Handle("/graphql/", http.StripPrefix("/graphql/", server.Handler(reg)))
+//
+// serves /graphql/<document>. The query is the "query" member of a JSON POST body, the whole of
+// an application/graphql POST body, which is lexed as it is read, or the query parameter of a GET. The request's context is passed to resolvers, so a principal
+// added by an authenticating handler (see auth.WithPrincipal) is checked against @auth rules.
+// The response is JSON with the data of the query, if it was executed, and its errors.
+func Handler(reg *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		document := r.Header.Get(DocumentHeader)
+		if len(document) == 0 {
+			document = strings.Trim(r.URL.Path, "/")
+		}
+		t, ok := reg.Lookup(document)
+		if !ok {
+			writeErrors(w, http.StatusNotFound, errors.New(`Document "`+document+`" is not registered`))
+			return
+		}
+
+		var (
+			query string
+			l     *lexer.Lexer
+		)
+		switch r.Method {
+		case http.MethodGet:
+			query = r.URL.Query().Get("query")
+		case http.MethodPost:
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/graphql" {
+				l = lexer.NewReader(http.MaxBytesReader(w, r.Body, int64(t.Limits.MaxQueryBytes)))
+				break
+			}
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, int64(t.Limits.MaxQueryBytes)+1024))
+			if err != nil {
+				writeErrors(w, http.StatusRequestEntityTooLarge, err)
+				return
+			}
+			var req request
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeErrors(w, http.StatusBadRequest, err)
+				return
+			}
+			query = req.Query
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeErrors(w, http.StatusMethodNotAllowed, errors.New("Method "+r.Method+" is not supported"))
+			return
+		}
+		if l == nil && len(strings.TrimSpace(query)) == 0 {
+			writeErrors(w, http.StatusBadRequest, errors.New("No query provided"))
+			return
+		}
+
+		id := requestID(r)
+		w.Header().Set(RequestIDHeader, id)
+		log := reg.logger().With(logger.RequestID, id, "document", document)
+		var (
+			result string
+			errs   []error
+		)
+		if l != nil {
+			result, errs = t.executeLexer(r.Context(), l, log, reg.tracing())
+			if l.Err() != nil {
+				writeErrors(w, http.StatusRequestEntityTooLarge, l.Err())
+				return
+			}
+		} else {
+			result, errs = t.execute(r.Context(), query, log, reg.tracing())
+		}
+		resp, err := parser.NewResponse(result, errs)
+		if err != nil {
+			log.Log(logger.Error, "response conversion failed", "error", err)
+			writeErrors(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeResponse(w, http.StatusOK, resp)
+	})
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...error) {
+	writeResponse(w, status, &parser.Response{Errors: errs})
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp *parser.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
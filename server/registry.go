@@ -0,0 +1,193 @@
+// Package server serves GraphQL documents against many named schemas (documents) from the one process.
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rosshpayne/graphql/lexer"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/parser"
+	"github.com/rosshpayne/graphql/resolver"
+	"github.com/rosshpayne/graphql/tracing"
+)
+
+// Limits applied to each request of a schema. Zero values take the default.
+type Limits struct {
+	ResolverTimeout time.Duration // default parser.ResolverTimeoutMS
+	MaxQueryBytes   int           // default DefaultMaxQueryBytes
+}
+
+const DefaultMaxQueryBytes = 1 << 20
+
+// Tenant is a named schema with its resolvers and limits.
+type Tenant struct {
+	Name      string
+	Schema    *parser.Schema
+	Resolvers *resolver.Resolvers
+	Limits    Limits
+}
+
+// Registry holds the schemas served by the process, keyed by document name.
+type Registry struct {
+	sync.RWMutex
+	tenants    map[string]*Tenant
+	log        logger.Logger
+	tracer     tracing.Tracer
+	tracerFunc func() tracing.Tracer
+}
+
+func NewRegistry() *Registry {
+	return &Registry{tenants: make(map[string]*Tenant), log: logger.Nop(), tracer: tracing.Nop()}
+}
+
+// SetLogger assigns the logger of requests served by the registry. Nothing is logged by default.
+func (r *Registry) SetLogger(log logger.Logger) {
+	r.Lock()
+	r.log = log
+	r.Unlock()
+}
+
+func (r *Registry) logger() logger.Logger {
+	r.RLock()
+	defer r.RUnlock()
+	return r.log
+}
+
+// SetTracer assigns the tracer of requests served by the registry, e.g. a *metrics.Metrics
+// shared by all requests. Nothing is traced by default.
+func (r *Registry) SetTracer(t tracing.Tracer) {
+	r.Lock()
+	r.tracer = t
+	r.Unlock()
+}
+
+// SetTracerFunc assigns a func returning a new tracer for each request, for tracers that record a single
+// request such as tracing.Apollo. Its tracers are notified along with the shared tracer of SetTracer.
+func (r *Registry) SetTracerFunc(f func() tracing.Tracer) {
+	r.Lock()
+	r.tracerFunc = f
+	r.Unlock()
+}
+
+// tracing returns the tracer of a request.
+func (r *Registry) tracing() tracing.Tracer {
+	r.RLock()
+	defer r.RUnlock()
+	if r.tracerFunc == nil {
+		return r.tracer
+	}
+	return tracing.Multi(r.tracer, r.tracerFunc())
+}
+
+// Add registers schema s under name. Resolvers must be registered before the schema is added
+// as they are shared by concurrent requests.
+func (r *Registry) Add(name string, s *parser.Schema, resolvers *resolver.Resolvers, limits Limits) error {
+	if len(name) == 0 {
+		return fmt.Errorf("No document name provided")
+	}
+	if s == nil {
+		return fmt.Errorf(`No schema provided for document "%s"`, name)
+	}
+	if resolvers == nil {
+		resolvers = resolver.New()
+	}
+	if limits.ResolverTimeout <= 0 {
+		limits.ResolverTimeout = parser.ResolverTimeoutMS * time.Millisecond
+	}
+	if limits.MaxQueryBytes <= 0 {
+		limits.MaxQueryBytes = DefaultMaxQueryBytes
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.tenants[name]; ok {
+		return fmt.Errorf(`Document "%s" is already registered`, name)
+	}
+	r.tenants[name] = &Tenant{Name: name, Schema: s, Resolvers: resolvers, Limits: limits}
+	return nil
+}
+
+// Remove unregisters document name. Requests in progress complete.
+func (r *Registry) Remove(name string) {
+	r.Lock()
+	delete(r.tenants, name)
+	r.Unlock()
+}
+
+// Lookup returns the tenant registered under document name.
+func (r *Registry) Lookup(name string) (*Tenant, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	t, ok := r.tenants[name]
+	return t, ok
+}
+
+// Documents returns the registered document names in sorted order.
+func (r *Registry) Documents() []string {
+	r.RLock()
+	defer r.RUnlock()
+	names := make([]string, 0, len(r.tenants))
+	for k := range r.tenants {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrUnknownDocument is returned by Execute when no schema is registered under the document name.
+var ErrUnknownDocument = fmt.Errorf("Document is not registered")
+
+// Execute parses, validates and executes query against the schema registered under document.
+func (r *Registry) Execute(document string, query string) (string, []error) {
+	return r.ExecuteContext(context.Background(), document, query)
+}
+
+// ExecuteContext is Execute with a context carrying request values, such as the principal.
+// A response with field errors, e.g. of fields denied to the principal, is returned with those errors.
+func (r *Registry) ExecuteContext(ctx context.Context, document string, query string) (string, []error) {
+	t, ok := r.Lookup(document)
+	if !ok {
+		return "", []error{fmt.Errorf(`"%s": %w`, document, ErrUnknownDocument)}
+	}
+	return t.execute(ctx, query, r.logger().With("document", document), r.tracing())
+}
+
+// Execute parses, validates and executes query against the tenant's schema.
+func (t *Tenant) Execute(query string) (string, []error) {
+	return t.execute(context.Background(), query, logger.Nop(), tracing.Nop())
+}
+
+func (t *Tenant) execute(ctx context.Context, query string, log logger.Logger, tracer tracing.Tracer) (string, []error) {
+	if len(query) > t.Limits.MaxQueryBytes {
+		log.Log(logger.Warn, "query size limit exceeded", "bytes", len(query), "limit", t.Limits.MaxQueryBytes)
+		return "", []error{fmt.Errorf(`Query exceeds the limit of %d bytes for document "%s"`, t.Limits.MaxQueryBytes, t.Name)}
+	}
+	return t.executeLexer(ctx, lexer.New(query), log, tracer)
+}
+
+// executeLexer is execute for a query read by l, which may be streaming it from a request body.
+// A query that could not be read in full is reported by its read error alone.
+func (t *Tenant) executeLexer(ctx context.Context, l *lexer.Lexer, log logger.Logger, tracer tracing.Tracer) (string, []error) {
+	start := time.Now()
+	p := parser.NewWithSchema(l, t.Schema)
+	p.SetLogger(log)
+	p.SetTracer(tracer)
+	p.SetContext(ctx)
+	p.Resolver = t.Resolvers
+	p.SetResolverTimeout(t.Limits.ResolverTimeout)
+	_, errs := p.ParseDocument()
+	if err := l.Err(); err != nil {
+		log.Log(logger.Warn, "query read failed", "error", err)
+		return "", []error{err}
+	}
+	if len(errs) > 0 {
+		log.Log(logger.Info, "document invalid", "errors", len(errs), "duration", time.Since(start))
+		return "", errs
+	}
+	result, errs := p.ExecuteDocument()
+	log.Log(logger.Info, "document executed", "errors", len(errs), "duration", time.Since(start))
+	return result, errs
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdl "github.com/rosshpayne/graph-sdl/ast"
+	"github.com/rosshpayne/graphql/auth"
+	"github.com/rosshpayne/graphql/logger"
+	"github.com/rosshpayne/graphql/metrics"
+	"github.com/rosshpayne/graphql/parser"
+	"github.com/rosshpayne/graphql/resolver"
+	"github.com/rosshpayne/graphql/tracing"
+)
+
+const starwarsSDL = `
+	schema {
+		query : Query
+	}
+	type Query { hero: [Character] }
+	type Character {
+		name: String!
+	}`
+
+const librarySDL = `
+	schema {
+		query : Query
+	}
+	type Query { hero: [Author] }
+	type Author {
+		name: String!
+		books: Int
+	}`
+
+func resolveWith(response string, delay time.Duration) resolver.ResolverFunc {
+	return func(ctx context.Context, resp sdl.InputValueProvider, args sdl.ObjectVals) <-chan string {
+		gql := make(chan string)
+		go func() {
+			time.Sleep(delay)
+			select {
+			case <-ctx.Done():
+			case gql <- response:
+			}
+		}()
+		return gql
+	}
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	reg := NewRegistry()
+	for _, v := range []struct {
+		name, sdl, response string
+		limits              Limits
+	}{
+		{"starwars", starwarsSDL, `{Character: [{name: "Luke"}] }`, Limits{}},
+		{"library", librarySDL, `{Author: [{name: "Tolkien", books: 12}] }`, Limits{MaxQueryBytes: 64}},
+	} {
+		s, errs := parser.NewSchema(v.sdl)
+		for _, e := range errs {
+			t.Fatalf("Schema %s: %s", v.name, e)
+		}
+		r := resolver.New()
+		if err := r.Register("Query/hero", resolveWith(v.response, 0)); err != nil {
+			t.Fatal(err)
+		}
+		if err := reg.Add(v.name, s, r, v.limits); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func trimWS(input string) string {
+	return strings.Join(strings.Fields(input), "")
+}
+
+func TestRegistry(t *testing.T) {
+
+	reg := newTestRegistry(t)
+
+	result, errs := reg.Execute("starwars", `query { hero { name } }`)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if !strings.Contains(result, `"Luke"`) {
+		t.Errorf("Unexpected result: %s", result)
+	}
+	result, errs = reg.Execute("library", `query { hero { name books } }`)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if !strings.Contains(trimWS(result), `books:12`) {
+		t.Errorf("Unexpected result: %s", result)
+	}
+	// books is not a field of the starwars schema
+	_, errs = reg.Execute("starwars", `query { hero { name books } }`)
+	if len(errs) == 0 {
+		t.Errorf("Expected error for field books")
+	}
+	_, errs = reg.Execute("library", `query { hero { name } }`+strings.Repeat(" ", 64))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "exceeds the limit of 64 bytes") {
+		t.Errorf("Expected query size error got %v", errs)
+	}
+	_, errs = reg.Execute("muppets", `query { hero { name } }`)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "not registered") {
+		t.Errorf("Expected unknown document error got %v", errs)
+	}
+	if err := reg.Add("starwars", &parser.Schema{}, nil, Limits{}); err == nil {
+		t.Errorf("Expected duplicate document error")
+	}
+	if got := fmt.Sprint(reg.Documents()); got != "[library starwars]" {
+		t.Errorf("Unexpected documents %s", got)
+	}
+}
+
+func TestResolverTimeoutLimit(t *testing.T) {
+
+	s, errs := parser.NewSchema(starwarsSDL)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	r := resolver.New()
+	r.Register("Query/hero", resolveWith(`{Character: [{name: "Luke"}] }`, 50*time.Millisecond))
+	reg := NewRegistry()
+	m := metrics.New()
+	reg.SetTracer(m)
+	reg.Add("slow", s, r, Limits{ResolverTimeout: 10 * time.Millisecond})
+
+	_, errs = reg.Execute("slow", `query { hero { name } }`)
+	if len(errs) == 0 {
+		t.Errorf("Expected resolver timeout error")
+	}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, v := range []string{
+		`graphql_operations_total{operation="",type="query"} 1`,
+		`graphql_operation_errors_total{operation="",type="query"} 1`,
+		`graphql_resolver_timeouts_total{field="Query.hero"} 1`,
+		`graphql_resolver_duration_seconds_count{field="Query.hero"} 1`,
+	} {
+		if !strings.Contains(string(body), v) {
+			t.Errorf("Expected %s in metrics:\n%s", v, body)
+		}
+	}
+}
+
+func TestHandlerTracing(t *testing.T) {
+
+	reg := newTestRegistry(t)
+	reg.SetTracerFunc(func() tracing.Tracer { return tracing.NewApollo() })
+	srv := httptest.NewServer(Handler(reg))
+	defer srv.Close()
+
+	// each response traces its own request only
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post(srv.URL+"/starwars", "application/json", strings.NewReader(`{"query": "query { hero { name } }"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if n := strings.Count(string(body), `"fieldName":"hero"`); n != 1 {
+			t.Errorf("Expected 1 resolver trace in response %d, got %d: %s", i, n, body)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+
+	reg := newTestRegistry(t)
+	var log strings.Builder
+	reg.SetLogger(logger.New(&log, logger.Info))
+	mux := http.NewServeMux()
+	mux.Handle("/graphql/", http.StripPrefix("/graphql/", Handler(reg)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	do := func(req *http.Request) (int, string) {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	// document from URL path
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/graphql/library", strings.NewReader(`{"query": "query { hero { name } }"}`))
+	if status, body := do(req); status != http.StatusOK || !strings.Contains(body, `"Tolkien"`) {
+		t.Errorf("Unexpected response %d %s", status, body)
+	}
+	// document from header takes precedence
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/graphql/library?query="+strings.Replace("query { hero { name } }", " ", "+", -1), nil)
+	req.Header.Set(DocumentHeader, "starwars")
+	req.Header.Set(RequestIDHeader, "req-42")
+	if status, body := do(req); status != http.StatusOK || !strings.Contains(body, `"Luke"`) {
+		t.Errorf("Unexpected response %d %s", status, body)
+	}
+	if !strings.Contains(log.String(), "INFO document executed request_id=req-42 document=starwars errors=0") {
+		t.Errorf("Expected request log, got: %s", log.String())
+	}
+	// validation errors
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/graphql/starwars", strings.NewReader(`{"query": "query { hero { books } }"}`))
+	if status, body := do(req); status != http.StatusOK || !strings.Contains(body, `"errors"`) {
+		t.Errorf("Unexpected response %d %s", status, body)
+	}
+	// application/graphql body is lexed as it is read
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/graphql/library", strings.NewReader("query { hero { name } }"))
+	req.Header.Set("Content-Type", "application/graphql; charset=utf-8")
+	if status, body := do(req); status != http.StatusOK || !strings.Contains(body, `"Tolkien"`) {
+		t.Errorf("Unexpected response %d %s", status, body)
+	}
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/graphql/library", strings.NewReader("query { hero { name "+strings.Repeat("name ", 20)+"} }"))
+	req.Header.Set("Content-Type", "application/graphql")
+	if status, body := do(req); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d got %d %s", http.StatusRequestEntityTooLarge, status, body)
+	}
+	// unknown document
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/graphql/muppets", strings.NewReader(`{"query": "query { hero { name } }"}`))
+	if status, _ := do(req); status != http.StatusNotFound {
+		t.Errorf("Expected status %d got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestHandlerPrincipal(t *testing.T) {
+
+	s, errs := parser.NewSchema(auth.Directive + `
+	schema {
+		query : Query
+	}
+	type Query { hero: [Author] }
+	type Author {
+		name: String!
+		books: Int @auth(requires: ["reader"])
+	}`)
+	for _, e := range errs {
+		t.Fatal(e)
+	}
+	r := resolver.New()
+	r.Register("Query/hero", resolveWith(`{Author: [{name: "Tolkien", books: 12}] }`, 0))
+	reg := NewRegistry()
+	reg.Add("library", s, r, Limits{})
+	// authenticate assigns the principal named in the request
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get("X-User"); len(user) > 0 {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{ID: user, Roles: []string{"reader"}}))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	srv := httptest.NewServer(authenticate(Handler(reg)))
+	defer srv.Close()
+
+	for _, user := range []string{"", "bilbo"} {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/library", strings.NewReader(`{"query": "query { hero { name books } }"}`))
+		req.Header.Set("X-User", user)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		var got struct {
+			Data struct {
+				Hero []struct {
+					Name  string
+					Books *int
+				}
+			}
+			Errors []struct {
+				Message    string
+				Path       []interface{}
+				Extensions struct{ Code string }
+			}
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("Response is not JSON: %s: %s", err, body)
+		}
+		if resp.StatusCode != http.StatusOK || len(got.Data.Hero) != 1 || got.Data.Hero[0].Name != "Tolkien" {
+			t.Errorf("Unexpected response %d %s", resp.StatusCode, body)
+			continue
+		}
+		// the data of a denied field is null and its error is reported with the data
+		if forbidden := got.Data.Hero[0].Books == nil; forbidden != (len(user) == 0) {
+			t.Errorf("User %q: unexpected response %s", user, body)
+		}
+		if forbidden := len(got.Errors) == 1 && got.Errors[0].Extensions.Code == auth.Forbidden && fmt.Sprint(got.Errors[0].Path) == "[hero 0 books]"; forbidden != (len(user) == 0) {
+			t.Errorf("User %q: unexpected errors %s", user, body)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Dir is a SchemaStore sourced from a directory tree. The types of a document are defined in
+// the .graphql files of the subdirectory of the same name, i.e. <root>/<document>/*.graphql.
+// A document's files are read on its first fetch.
+type Dir struct {
+	root string
+	sync.Mutex
+	loaded map[string][]error // documents read from the directory and any errors found
+	mem    *Memory
+}
+
+func NewDir(root string) *Dir {
+	return &Dir{root: root, loaded: make(map[string][]error), mem: NewMemory()}
+}
+
+func (d *Dir) FetchType(document string, name string) (string, error) {
+	d.Lock()
+	errs, ok := d.loaded[document]
+	if !ok {
+		errs = d.load(document)
+		d.loaded[document] = errs
+	}
+	d.Unlock()
+	if len(errs) > 0 {
+		return "", fmt.Errorf(`Document "%s" in %s has errors: %s`, document, d.root, errs[0])
+	}
+	return d.mem.FetchType(document, name)
+}
+
+func (d *Dir) load(document string) []error {
+	files, err := filepath.Glob(filepath.Join(d.root, document, "*.graphql"))
+	if err != nil {
+		return []error{err}
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		sdl, err := ioutil.ReadFile(f)
+		if err != nil {
+			return []error{err}
+		}
+		if errs := d.mem.AddSDL(document, string(sdl)); len(errs) > 0 {
+			for i, e := range errs {
+				errs[i] = fmt.Errorf("%s: %w", filepath.Base(f), e)
+			}
+			return errs
+		}
+	}
+	return nil
+}
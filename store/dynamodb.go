@@ -0,0 +1,90 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+const (
+	DefaultRegion = "us-east-1"
+	DefaultTable  = "GraphQL2"
+)
+
+type typeRow struct {
+	PKey  string
+	SortK string
+	Stmt  string
+	Type  string
+}
+
+type pkRow struct {
+	PKey  string // type name
+	SortK string // document
+}
+
+// DynamoDB is a SchemaStore backed by the DynamoDB table graph-sdl persists types to.
+// The session is opened on the first fetch.
+type DynamoDB struct {
+	region string
+	table  string
+	once   sync.Once
+	db     *dynamodb.DynamoDB
+	err    error
+}
+
+func NewDynamoDB(region string, table string) *DynamoDB {
+	if len(region) == 0 {
+		region = DefaultRegion
+	}
+	if len(table) == 0 {
+		table = DefaultTable
+	}
+	return &DynamoDB{region: region, table: table}
+}
+
+func (d *DynamoDB) open() {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(d.region),
+	})
+	if err != nil {
+		d.err = fmt.Errorf("Error in creating DynamoDB session: %w", err)
+		return
+	}
+	d.db = dynamodb.New(sess, aws.NewConfig())
+}
+
+func (d *DynamoDB) FetchType(document string, name string) (string, error) {
+	if len(name) == 0 {
+		return "", fmt.Errorf("No DB search value provided")
+	}
+	d.once.Do(d.open)
+	if d.err != nil {
+		return "", d.err
+	}
+	av, err := dynamodbattribute.MarshalMap(&pkRow{PKey: name, SortK: document})
+	if err != nil {
+		return "", fmt.Errorf("Error in marshall of pKey. MarshalMap: %w", err)
+	}
+	input := &dynamodb.GetItemInput{
+		Key:       av,
+		TableName: aws.String(d.table),
+	}
+	input = input.SetConsistentRead(false)
+	result, err := d.db.GetItem(input)
+	if err != nil {
+		return "", fmt.Errorf(`Error in GetItem of Pkey: "%s", SortK: "%s": %w`, name, document, err)
+	}
+	if len(result.Item) == 0 {
+		return "", notFound(document, name)
+	}
+	rec := &typeRow{}
+	if err = dynamodbattribute.UnmarshalMap(result.Item, rec); err != nil {
+		return "", fmt.Errorf(`Error in unmarshal of Pkey: "%s", SortK: "%s": %w`, name, document, err)
+	}
+	return rec.Stmt, nil
+}
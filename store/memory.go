@@ -0,0 +1,68 @@
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// Memory is a SchemaStore held in memory. It is populated with AddSDL or AddType.
+type Memory struct {
+	sync.RWMutex
+	docs map[string]map[string]string // document -> type name -> SDL statement
+}
+
+func NewMemory() *Memory {
+	return &Memory{docs: make(map[string]map[string]string)}
+}
+
+// AddSDL parses a SDL document and adds its types to document. Types already held are replaced.
+// Nothing is added when the SDL contains errors.
+func (m *Memory) AddSDL(document string, sdl string) []error {
+	types, errs := ParseSDL(sdl)
+	if len(errs) > 0 {
+		return errs
+	}
+	m.Lock()
+	defer m.Unlock()
+	for name, stmt := range types {
+		m.addType(document, name, stmt)
+	}
+	return nil
+}
+
+// AddType adds the SDL statement of type name to document.
+func (m *Memory) AddType(document string, name string, stmt string) {
+	m.Lock()
+	m.addType(document, name, stmt)
+	m.Unlock()
+}
+
+func (m *Memory) addType(document string, name string, stmt string) {
+	d, ok := m.docs[document]
+	if !ok {
+		d = make(map[string]string)
+		m.docs[document] = d
+	}
+	d[name] = stmt
+}
+
+func (m *Memory) FetchType(document string, name string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	if stmt, ok := m.docs[document][name]; ok {
+		return stmt, nil
+	}
+	return "", notFound(document, name)
+}
+
+// Types returns the names of the types held for document in sorted order.
+func (m *Memory) Types(document string) []string {
+	m.RLock()
+	defer m.RUnlock()
+	names := make([]string, 0, len(m.docs[document]))
+	for name := range m.docs[document] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
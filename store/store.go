@@ -0,0 +1,89 @@
+// Package store holds the SDL type definitions referenced by GraphQL documents.
+//
+// Each type is saved as the text of its SDL statement and is keyed by the type name and the
+// document (schema) it belongs to. Directives are named with their leading "@" and the
+// schema statement is named "schema".
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	lsdl "github.com/rosshpayne/graph-sdl/lexer"
+	psdl "github.com/rosshpayne/graph-sdl/parser"
+)
+
+// ErrNotFound is returned (wrapped) by FetchType when a type is not defined in a document.
+var ErrNotFound = errors.New("does not exist in document")
+
+// SchemaStore sources the SDL statement of a type defined in a document.
+type SchemaStore interface {
+	FetchType(document string, name string) (string, error)
+}
+
+func notFound(document string, name string) error {
+	return fmt.Errorf(`"%s" %w "%s"`, name, ErrNotFound, document)
+}
+
+// statement keywords of a type system definition
+var stmtKeywords = map[string]bool{
+	"TYPE":      true,
+	"Enum":      true,
+	"INTERFACE": true,
+	"UNION":     true,
+	"INPUT":     true,
+	"SCALAR":    true,
+	"DIRECTIVE": true,
+	"SCHEMA":    true,
+}
+
+// ParseSDL splits a SDL document into its statements, keyed by type name. Type extensions
+// are not supported as they depend on an existing definition of the type.
+func ParseSDL(input string) (map[string]string, []error) {
+	//
+	// count the statements, which start with a keyword outside of any braces or parentheses.
+	//
+	var (
+		depth int
+		stmts int
+	)
+	l := lsdl.New(input)
+	for tok := l.NextToken(); tok.Type != "EOF"; tok = l.NextToken() {
+		switch tok.Type {
+		case "{", "(":
+			depth++
+		case "}", ")":
+			depth--
+		case "EXTEND":
+			if depth == 0 {
+				return nil, []error{fmt.Errorf("Type extensions are not supported in a schema store at line: %d, column: %d", tok.Loc.Line, tok.Loc.Col)}
+			}
+		case "ILLEGAL":
+			return nil, []error{fmt.Errorf(`Illegal token "%s" at line: %d, column: %d`, tok.Literal, tok.Loc.Line, tok.Loc.Col)}
+		default:
+			if depth == 0 && stmtKeywords[string(tok.Type)] {
+				stmts++
+			}
+		}
+	}
+	//
+	// parse each statement and save its canonical form
+	//
+	types := make(map[string]string, stmts)
+	p := psdl.New(lsdl.New(input))
+	for i := 0; i < stmts; i++ {
+		stmt := p.ParseStatement()
+		if errs := p.Getperror(); len(errs) > 0 {
+			return nil, errs
+		}
+		if stmt == nil {
+			break
+		}
+		name := stmt.TypeName().String()
+		if _, ok := types[name]; ok {
+			return nil, []error{fmt.Errorf(`Duplicate type "%s" in schema`, name)}
+		}
+		types[name] = stmt.String()
+	}
+	return types, nil
+}
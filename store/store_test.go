@@ -0,0 +1,110 @@
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSDL = `
+	schema {
+		query : Query
+	}
+	type Query { hero(episode: Episode): [Character] }
+	enum Episode { NEWHOPE EMPIRE JEDI }
+	interface Character {
+		id: ID!
+		name: String!
+	}
+	type Human implements Character {
+		id: ID!
+		name: String!
+	}
+	union SearchResult = Human
+	input ReviewInput { stars: Int! }
+	scalar Time
+	directive @cache(maxAge: Int) on FIELD | QUERY`
+
+func TestParseSDL(t *testing.T) {
+
+	types, errs := ParseSDL(testSDL)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	for _, name := range []string{"schema", "Query", "Episode", "Character", "Human", "SearchResult", "ReviewInput", "Time", "@cache"} {
+		if _, ok := types[name]; !ok {
+			t.Errorf(`Expected type "%s"`, name)
+		}
+	}
+	if len(types) != 9 {
+		t.Errorf("Expected 9 types got %d", len(types))
+	}
+	// the saved statements are valid SDL
+	for name, stmt := range types {
+		again, errs := ParseSDL(stmt)
+		if len(errs) > 0 || len(again) != 1 {
+			t.Errorf(`Statement of "%s" does not parse: %q %v`, name, stmt, errs)
+		}
+	}
+}
+
+func TestParseSDLErrors(t *testing.T) {
+
+	if _, errs := ParseSDL(`extend type Query { villain: Character }`); len(errs) == 0 {
+		t.Errorf("Expected error for type extension")
+	}
+	if _, errs := ParseSDL(`enum Episode { NEWHOPE } enum Episode { JEDI }`); len(errs) == 0 {
+		t.Errorf("Expected error for duplicate type")
+	}
+}
+
+func TestMemory(t *testing.T) {
+
+	m := NewMemory()
+	if errs := m.AddSDL("Starwars", testSDL); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	stmt, err := m.FetchType("Starwars", "Episode")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(stmt, "NEWHOPE") {
+		t.Errorf("Unexpected statement %q", stmt)
+	}
+	_, err = m.FetchType("DefaultDoc", "Episode")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+	if err != nil && err.Error() != `"Episode" does not exist in document "DefaultDoc"` {
+		t.Errorf("Unexpected error message %q", err)
+	}
+}
+
+func TestDir(t *testing.T) {
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "Starwars"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "Starwars", "schema.graphql"), []byte(testSDL), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "Starwars", "review.graphql"), []byte(`type Review { stars: Int }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDir(root)
+	for _, name := range []string{"Human", "Review", "@cache"} {
+		if _, err := d.FetchType("Starwars", name); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	}
+	if _, err := d.FetchType("Starwars", "Droid"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+	if _, err := d.FetchType("Empire", "Human"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+}
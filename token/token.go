@@ -49,6 +49,8 @@ const (
 	UNDERSSCRE = "_"
 	DOLLAR     = "$"
 	ATSIGN     = "@"
+	PIPE       = "|"
+	AMPERSAND  = "&"
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -65,6 +67,8 @@ const (
 
 	BOM = "BOM"
 
+	WHITESPACE = "WHITESPACE"
+
 	// Keywords
 	QUERY        = "QUERY"
 	MUTATION     = "MUTATION"
@@ -91,6 +95,18 @@ type Token struct {
 	Literal      string // string value of token - rune, string, int, float, bool
 	Loc          Pos    // start location (line,col) of token
 	Illegal      bool
+	Offset       int      // byte offset of the token in the input
+	End          int      // byte offset following the token
+	Leading      []Trivia // trivia before the token, when the lexer keeps trivia
+	Trailing     []Trivia // trivia after the token on the same line, when the lexer keeps trivia
+}
+
+// Trivia is source text between tokens that the parser ignores: white space, a comma or a comment.
+type Trivia struct {
+	Type    TokenType // WHITESPACE, COMMA or COMMENT
+	Literal string
+	Loc     Pos
+	Offset  int // byte offset in the input
 }
 
 func (t *Token) AtPosition() string {
@@ -0,0 +1,220 @@
+// Package tracing times the phases of a request and each resolver call.
+//
+// The parser starts a span for the parse and validate phases of a document, for every
+// resolver it invokes and for the serialisation of each statement's response. A Tracer
+// that also implements Extender adds its results to the response's extensions, as Apollo does.
+package tracing
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Kind int
+
+const (
+	Parse Kind = iota
+	Validate
+	Resolve
+	Serialize
+	Operation
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Parse:
+		return "parse"
+	case Validate:
+		return "validate"
+	case Resolve:
+		return "resolve"
+	case Serialize:
+		return "serialize"
+	case Operation:
+		return "operation"
+	}
+	return "unknown"
+}
+
+// Span describes the work being timed. The field members are assigned for Resolve spans only,
+// the operation members for Operation spans, which time the execution of a statement.
+type Span struct {
+	Kind          Kind
+	Path          string // path of the field in the response, e.g. "hero/friends/name"
+	ParentType    string // type the field belongs to
+	FieldName     string
+	ReturnType    string // SDL type of the field, e.g. "[Character]"
+	Operation     string // statement name, empty for a shorthand query
+	OperationType string // query, mutation or subscription
+}
+
+// Tracer is notified of the start of a span. The returned func is called when the span ends,
+// with the error, if any, that ended it. Spans of different resolvers may be concurrent.
+type Tracer interface {
+	Start(span Span) (finish func(err error))
+}
+
+// Extender is implemented by a Tracer that contributes to the extensions member of the response.
+type Extender interface {
+	Extensions() map[string]interface{}
+}
+
+type nop struct{}
+
+func (nop) Start(Span) func(error) { return func(error) {} }
+
+// Nop returns a Tracer that records nothing.
+func Nop() Tracer {
+	return nop{}
+}
+
+type multi []Tracer
+
+// Multi returns a Tracer that notifies each of tracers. Its extensions are those of the tracers that implement Extender.
+func Multi(tracers ...Tracer) Tracer {
+	return multi(tracers)
+}
+
+func (m multi) Start(span Span) func(error) {
+	finish := make([]func(error), len(m))
+	for i, t := range m {
+		finish[i] = t.Start(span)
+	}
+	return func(err error) {
+		for _, f := range finish {
+			f(err)
+		}
+	}
+}
+
+func (m multi) Extensions() map[string]interface{} {
+	ext := make(map[string]interface{})
+	for _, t := range m {
+		if e, ok := t.(Extender); ok {
+			for k, v := range e.Extensions() {
+				ext[k] = v
+			}
+		}
+	}
+	return ext
+}
+
+// Apollo records spans in the Apollo tracing format (version 1), reported under extensions.tracing.
+// An Apollo tracer records a single request.
+type Apollo struct {
+	sync.Mutex
+	start      time.Time
+	end        time.Time
+	parsing    phase
+	validation phase
+	resolvers  []ResolverTrace
+}
+
+type phase struct {
+	StartOffset int64 `json:"startOffset"`
+	Duration    int64 `json:"duration"`
+}
+
+// ResolverTrace is the timing of one resolver call. Offsets and durations are in nanoseconds.
+type ResolverTrace struct {
+	Path        []interface{} `json:"path"`
+	ParentType  string        `json:"parentType"`
+	FieldName   string        `json:"fieldName"`
+	ReturnType  string        `json:"returnType"`
+	StartOffset int64         `json:"startOffset"`
+	Duration    int64         `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// Trace is the content of extensions.tracing.
+type Trace struct {
+	Version    int       `json:"version"`
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	Duration   int64     `json:"duration"`
+	Parsing    phase     `json:"parsing"`
+	Validation phase     `json:"validation"`
+	Execution  struct {
+		Resolvers []ResolverTrace `json:"resolvers"`
+	} `json:"execution"`
+}
+
+func NewApollo() *Apollo {
+	return &Apollo{start: time.Now()}
+}
+
+func (a *Apollo) Start(span Span) func(error) {
+	start := time.Now()
+	return func(err error) {
+		end := time.Now()
+		a.Lock()
+		defer a.Unlock()
+		if end.After(a.end) {
+			a.end = end
+		}
+		p := phase{StartOffset: start.Sub(a.start).Nanoseconds(), Duration: end.Sub(start).Nanoseconds()}
+		switch span.Kind {
+		case Parse:
+			a.parsing = p
+		case Validate:
+			a.validation = p
+		case Resolve:
+			r := ResolverTrace{
+				Path:        responsePath(span.Path),
+				ParentType:  span.ParentType,
+				FieldName:   span.FieldName,
+				ReturnType:  span.ReturnType,
+				StartOffset: p.StartOffset,
+				Duration:    p.Duration,
+			}
+			if err != nil {
+				r.Error = err.Error()
+			}
+			a.resolvers = append(a.resolvers, r)
+		}
+	}
+}
+
+// Trace returns the spans recorded so far.
+func (a *Apollo) Trace() Trace {
+	a.Lock()
+	defer a.Unlock()
+	t := Trace{Version: 1, StartTime: a.start, EndTime: a.end, Parsing: a.parsing, Validation: a.validation}
+	if t.EndTime.Before(t.StartTime) {
+		t.EndTime = t.StartTime
+	}
+	t.Duration = t.EndTime.Sub(t.StartTime).Nanoseconds()
+	t.Execution.Resolvers = append([]ResolverTrace{}, a.resolvers...)
+	return t
+}
+
+func (a *Apollo) Extensions() map[string]interface{} {
+	return map[string]interface{}{"tracing": a.Trace()}
+}
+
+// responsePath converts a field path to the array form of the Apollo trace.
+// The executor does not track list indexes, so these are absent.
+func responsePath(path string) []interface{} {
+	var p []interface{}
+	for _, v := range strings.Split(strings.Trim(path, "/"), "/") {
+		if len(v) > 0 {
+			p = append(p, v)
+		}
+	}
+	return p
+}
+
+// MarshalExtensions returns the JSON form of the extensions of tracer t, or nil if it has none.
+func MarshalExtensions(t Tracer) ([]byte, error) {
+	e, ok := t.(Extender)
+	if !ok {
+		return nil, nil
+	}
+	ext := e.Extensions()
+	if len(ext) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ext)
+}
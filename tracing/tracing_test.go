@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestApollo(t *testing.T) {
+
+	a := NewApollo()
+	a.Start(Span{Kind: Parse})(nil)
+	a.Start(Span{Kind: Validate})(nil)
+	var wg sync.WaitGroup
+	for _, path := range []string{"hero", "hero/friends"} {
+		path := path
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			finish := a.Start(Span{Kind: Resolve, Path: path, ParentType: "Query", FieldName: "hero", ReturnType: "[Character]"})
+			if path == "hero" {
+				finish(nil)
+			} else {
+				finish(errors.New("timed out"))
+			}
+		}()
+	}
+	wg.Wait()
+	a.Start(Span{Kind: Serialize})(nil)
+
+	tr := a.Trace()
+	if tr.Version != 1 || tr.Duration < 0 || tr.EndTime.Before(tr.StartTime) {
+		t.Errorf("Unexpected trace %+v", tr)
+	}
+	if tr.Validation.StartOffset < tr.Parsing.StartOffset {
+		t.Errorf("Expected validation to follow parsing: %+v %+v", tr.Parsing, tr.Validation)
+	}
+	if len(tr.Execution.Resolvers) != 2 {
+		t.Fatalf("Expected 2 resolvers, got %d", len(tr.Execution.Resolvers))
+	}
+	for _, r := range tr.Execution.Resolvers {
+		switch len(r.Path) {
+		case 1:
+			if r.Path[0] != "hero" || len(r.Error) > 0 {
+				t.Errorf("Unexpected resolver %+v", r)
+			}
+		case 2:
+			if r.Path[1] != "friends" || r.Error != "timed out" {
+				t.Errorf("Unexpected resolver %+v", r)
+			}
+		default:
+			t.Errorf("Unexpected path %v", r.Path)
+		}
+	}
+
+	b, err := MarshalExtensions(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ext map[string]map[string]interface{}
+	if err := json.Unmarshal(b, &ext); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"version", "startTime", "endTime", "duration", "parsing", "validation", "execution"} {
+		if _, ok := ext["tracing"][k]; !ok {
+			t.Errorf(`Expected "%s" in tracing extension: %s`, k, b)
+		}
+	}
+	if !strings.Contains(string(b), `"parentType":"Query"`) {
+		t.Errorf("Expected resolver parentType in %s", b)
+	}
+}
+
+func TestNop(t *testing.T) {
+	Nop().Start(Span{Kind: Resolve})(errors.New("ignored"))
+	if b, err := MarshalExtensions(Nop()); b != nil || err != nil {
+		t.Errorf("Expected no extensions from Nop tracer, got %s %v", b, err)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	a := NewApollo()
+	m := Multi(Nop(), a)
+	m.Start(Span{Kind: Resolve, Path: "hero", ParentType: "Query", FieldName: "hero"})(nil)
+	if n := len(a.Trace().Execution.Resolvers); n != 1 {
+		t.Errorf("Expected 1 resolver, got %d", n)
+	}
+	if b, err := MarshalExtensions(m); err != nil || !strings.Contains(string(b), `"tracing"`) {
+		t.Errorf("Expected tracing extension, got %s %v", b, err)
+	}
+	if b, _ := MarshalExtensions(Multi(Nop())); b != nil {
+		t.Errorf("Expected no extensions, got %s", b)
+	}
+}